@@ -1,19 +1,38 @@
 package allure
 
 import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 )
 
 // Attachment - is an implementation of the attachments to the report in allure. It is most often used to contain
 // screenshots, responses, files and other data obtained during the test.
 type Attachment struct {
-	Name    string   `json:"name,omitempty"`   // Attachment name
-	Source  string   `json:"source,omitempty"` // Path to the Attachment file (name)
-	Type    MimeType `json:"type,omitempty"`   // Mime-type of the Attachment
-	UUID    string   `json:"uuid,omitempty"`   // Unique identifier of the Attachment
-	content []byte   // Attachment's content as bytes array
+	Name     string                  `json:"name,omitempty"`   // Attachment name
+	Source   string                  `json:"source,omitempty"` // Path to the Attachment file (name)
+	Type     MimeType                `json:"type,omitempty"`   // Mime-type of the Attachment
+	UUID     string                  `json:"uuid,omitempty"`   // Unique identifier of the Attachment
+	content  []byte                  // Attachment's content as bytes array
+	producer func(w io.Writer) error // Lazily evaluated Attachment's content
+	written  bool                    // Attachment's content has been already written to the Sink
+	redacted bool                    // Some secrets have been redacted from the content (see RedactValues)
 }
 
+// AttachMode describes how an existing file becomes an Attachment
+type AttachMode int
+
+// AttachMode constants
+const (
+	CopyFile AttachMode = iota // The file is copied to the results
+	LinkFile                   // The file is hard-linked to the results (copied if linking is impossible)
+	MoveFile                   // The file is moved to the results (copied and removed if moving is impossible)
+)
+
 // MimeType is Attachment's mime type.
 // See more: https://developer.mozilla.org/en-US/docs/Web/HTTP/Basics_of_HTTP/MIME_types/Common_types
 type MimeType string
@@ -47,9 +66,15 @@ const (
 	Xlsx MimeType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
 )
 
-// Ext returns file extension for this mime-type
+// Ext returns file extension for this mime-type.
+// Extensions registered with RegisterMimeType take precedence over the built-in ones.
+// Unknown mime-types have no extension, so the file names don't depend on the mime-types table of the host.
 func (mt MimeType) Ext() string {
-	switch mt {
+	if ext, ok := mt.registeredExt(); ok {
+		return ext
+	}
+
+	switch mt.base() {
 	case Text:
 		return ".txt"
 	case Csv:
@@ -98,7 +123,12 @@ func (mt MimeType) Ext() string {
 }
 
 // NewAttachment - Constructor. Returns pointer to new attachment object.
+// If mimeType is empty, it is detected from the content.
 func NewAttachment(name string, mimeType MimeType, content []byte) *Attachment {
+	if mimeType == "" {
+		mimeType = DetectMimeType(content)
+	}
+
 	id := uuid.New().String()
 
 	return &Attachment{
@@ -110,15 +140,185 @@ func NewAttachment(name string, mimeType MimeType, content []byte) *Attachment {
 	}
 }
 
+// NewAttachmentFromReader - Constructor. Writes the content of the reader to the process-wide Sink
+// and returns pointer to new attachment object. The content is not kept in memory.
+// If mimeType is empty, it is detected from the first bytes of the content.
+func NewAttachmentFromReader(name string, mimeType MimeType, content io.Reader) (*Attachment, error) {
+	return NewAttachmentFromReaderTo(nil, name, mimeType, content)
+}
+
+// NewAttachmentFromReaderTo - Constructor. Same as NewAttachmentFromReader, but writes the content to the passed sink.
+// The sink must be the one the owning result or container is printed to (see Result.GetSink).
+// If sink is nil, the process-wide Sink is used.
+func NewAttachmentFromReaderTo(sink Sink, name string, mimeType MimeType, content io.Reader) (*Attachment, error) {
+	if mimeType == "" {
+		buffered := bufio.NewReaderSize(content, sniffLen)
+		head, _ := buffered.Peek(sniffLen)
+		mimeType = DetectMimeType(head)
+		content = buffered
+	}
+
+	attachment := newEmptyAttachment(name, mimeType, mimeType.Ext())
+
+	if err := attachment.writeFrom(sinkOrDefault(sink), content); err != nil {
+		return nil, errors.Wrapf(err, "Cannot write attachment %s", name)
+	}
+	attachment.written = true
+
+	return attachment, nil
+}
+
+// NewAttachmentFromFile - Constructor. Puts the existing file to the process-wide Sink according to the mode
+// and returns pointer to new attachment object. The content is not kept in memory.
+// If mimeType is empty, it is detected from the file extension or from the first bytes of the file,
+// and the source keeps the extension of the file.
+func NewAttachmentFromFile(name string, mimeType MimeType, path string, mode AttachMode) (*Attachment, error) {
+	return NewAttachmentFromFileTo(nil, name, mimeType, path, mode)
+}
+
+// NewAttachmentFromFileTo - Constructor. Same as NewAttachmentFromFile, but puts the file to the passed sink.
+// The sink must be the one the owning result or container is printed to (see Result.GetSink).
+// If sink is nil, the process-wide Sink is used.
+func NewAttachmentFromFileTo(sink Sink, name string, mimeType MimeType, path string, mode AttachMode) (*Attachment, error) {
+	explicitType := mimeType != ""
+	if !explicitType {
+		mimeType = detectFileMimeType(path)
+	}
+
+	// the file keeps its own extension unless the mime-type is set explicitly
+	ext := filepath.Ext(path)
+	if typeExt := mimeType.Ext(); typeExt != "" && (explicitType || ext == "") {
+		ext = typeExt
+	}
+
+	attachment := newEmptyAttachment(name, mimeType, ext)
+
+	if err := attachFile(sinkOrDefault(sink), attachment, path, mode); err != nil {
+		return nil, errors.Wrapf(err, "Cannot attach file %s", path)
+	}
+	attachment.written = true
+
+	return attachment, nil
+}
+
+// NewLazyAttachment - Constructor. Returns pointer to new attachment object, whose content is produced
+// only when the attachment is printed. The produced content is streamed to the Sink.
+func NewLazyAttachment(name string, mimeType MimeType, producer func(w io.Writer) error) *Attachment {
+	attachment := newEmptyAttachment(name, mimeType, mimeType.Ext())
+	attachment.producer = producer
+
+	return attachment
+}
+
+func newEmptyAttachment(name string, mimeType MimeType, ext string) *Attachment {
+	id := uuid.New().String()
+
+	return &Attachment{
+		UUID:   id,
+		Name:   name,
+		Type:   mimeType,
+		Source: id + "-attachment" + ext,
+	}
+}
+
+// attachFile puts the file from path to the sink as the attachment's source according to the mode.
+// The text files are always copied if their content must be redacted.
+func attachFile(sink Sink, attachment *Attachment, path string, mode AttachMode) error {
+	name := attachment.Source
+
+	target := sink
+	if batch, ok := sink.(*Batch); ok {
+		// the batch writes the files to its sink, the file is linked or moved there directly
+		target = sinkOrDefault(batch.sink)
+	}
+
+	if ds, ok := target.(dirSink); ok && mode != CopyFile && !attachment.shouldRedact() {
+		dir, err := ds.outputDir()
+		if err != nil {
+			return err
+		}
+
+		targetPath := filepath.Join(dir, name)
+
+		switch mode {
+		case LinkFile:
+			if os.Link(path, targetPath) == nil {
+				return nil
+			}
+		case MoveFile:
+			if os.Rename(path, targetPath) == nil {
+				return nil
+			}
+		}
+	}
+
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+
+	err = attachment.writeFrom(sink, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil && mode == MoveFile {
+		err = os.Remove(path)
+	}
+
+	return err
+}
+
 func (a *Attachment) GetUUID() string {
 	return a.UUID
 }
 
+// GetContent returns attachment's content. It is empty for the streamed, file-backed and lazy attachments.
 func (a *Attachment) GetContent() []byte {
 	return a.content
 }
 
-// Print - Creates a file from `Attachment.content`. The file type is determined by its `Attachment.mimeType`.
+// Print - Creates a file from `Attachment.content` in the process-wide Sink.
+// The file type is determined by its `Attachment.mimeType`.
 func (a *Attachment) Print() error {
-	return NewFileManager().CreateFile(a.Source, a.content)
+	return a.printTo(GetSink())
+}
+
+func (a *Attachment) printTo(sink Sink) error {
+	switch {
+	case a.written:
+		return nil
+
+	case a.producer != nil:
+		reader, writer := io.Pipe()
+		go func() {
+			_ = writer.CloseWithError(a.producer(writer))
+		}()
+
+		err := a.writeFrom(sink, reader)
+		_ = reader.CloseWithError(err)
+
+		return err
+
+	case a.shouldRedact():
+		return sink.CreateFile(a.Source, a.redactContent(a.content))
+
+	default:
+		return sink.CreateFile(a.Source, a.content)
+	}
+}
+
+// copyAttachments returns the copies of the attachments, the content is shared
+func copyAttachments(attachments []*Attachment) []*Attachment {
+	if attachments == nil {
+		return nil
+	}
+
+	copied := make([]*Attachment, 0, len(attachments))
+	for _, attachment := range attachments {
+		attachmentCopy := *attachment
+		copied = append(copied, &attachmentCopy)
+	}
+
+	return copied
 }
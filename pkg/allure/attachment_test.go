@@ -2,7 +2,10 @@ package allure
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -82,3 +85,105 @@ func TestAttachment_GetContent(t *testing.T) {
 	attachment := NewAttachment(testAttachName, Text, content)
 	require.Equal(t, content, attachment.GetContent())
 }
+
+func TestNewAttachmentFromReader(t *testing.T) {
+	sink := NewMemorySink()
+	SetSink(sink)
+	defer SetSink(nil)
+
+	attachment, err := NewAttachmentFromReader("reader", Text, strings.NewReader("some content"))
+	require.NoError(t, err)
+	require.Nil(t, attachment.GetContent())
+	require.True(t, strings.HasSuffix(attachment.Source, Text.Ext()))
+
+	content, ok := sink.File(attachment.Source)
+	require.True(t, ok)
+	require.Equal(t, "some content", string(content))
+
+	sink.Reset()
+	require.NoError(t, attachment.Print())
+	require.Empty(t, sink.Files())
+}
+
+func TestNewAttachmentFromFile(t *testing.T) {
+	for _, mode := range []AttachMode{CopyFile, LinkFile, MoveFile} {
+		t.Run(fmt.Sprintf("mode %d", mode), func(t *testing.T) {
+			dir := t.TempDir()
+			SetSink(NewFileSink(filepath.Join(dir, "allure-results")))
+			defer SetSink(nil)
+
+			path := filepath.Join(dir, "some.log")
+			require.NoError(t, os.WriteFile(path, []byte("some content"), fileSystemPermissionCode))
+
+			attachment, err := NewAttachmentFromFile("file", "", path, mode)
+			require.NoError(t, err)
+			require.True(t, strings.HasSuffix(attachment.Source, ".log"))
+
+			content, err := os.ReadFile(filepath.Join(dir, "allure-results", attachment.Source))
+			require.NoError(t, err)
+			require.Equal(t, "some content", string(content))
+
+			_, err = os.Stat(path)
+			require.Equal(t, mode == MoveFile, os.IsNotExist(err))
+		})
+	}
+}
+
+func TestNewAttachmentTo_resultSink(t *testing.T) {
+	global := NewMemorySink()
+	SetSink(global)
+	defer SetSink(nil)
+
+	sink := NewMemorySink()
+	result := NewResult("test", "TestAttachment").WithSink(sink)
+	require.Equal(t, sink, result.GetSink())
+
+	fromReader, err := NewAttachmentFromReaderTo(result.GetSink(), "reader", Text, strings.NewReader("reader content"))
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "some.log")
+	require.NoError(t, os.WriteFile(path, []byte("file content"), fileSystemPermissionCode))
+
+	fromFile, err := NewAttachmentFromFileTo(result.GetSink(), "file", "", path, LinkFile)
+	require.NoError(t, err)
+
+	result.Attachments = append(result.Attachments, fromReader, fromFile)
+	require.NoError(t, result.Print())
+
+	for _, attachment := range []*Attachment{fromReader, fromFile} {
+		_, ok := sink.File(attachment.Source)
+		require.True(t, ok, attachment.Name)
+		_, ok = global.File(attachment.Source)
+		require.False(t, ok, attachment.Name)
+	}
+	require.Empty(t, global.Files())
+}
+
+func TestNewAttachmentFromFile_notExists(t *testing.T) {
+	_, err := NewAttachmentFromFile("file", Text, filepath.Join(t.TempDir(), "not_exists.txt"), CopyFile)
+	require.Error(t, err)
+}
+
+func TestNewLazyAttachment(t *testing.T) {
+	var called bool
+
+	attachment := NewLazyAttachment("lazy", Text, func(w io.Writer) error {
+		called = true
+		_, err := io.WriteString(w, "some content")
+		return err
+	})
+	require.False(t, called)
+
+	sink := NewMemorySink()
+	require.NoError(t, attachment.printTo(sink))
+	require.True(t, called)
+
+	content, ok := sink.File(attachment.Source)
+	require.True(t, ok)
+	require.Equal(t, "some content", string(content))
+
+	failed := NewLazyAttachment("lazy", Text, func(w io.Writer) error {
+		return fmt.Errorf("whoops")
+	})
+	require.EqualError(t, failed.printTo(NewMemorySink()), "whoops")
+}
@@ -0,0 +1,108 @@
+package allure
+
+import (
+	"regexp"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+)
+
+// Category is the implementation of the entry of the `categories.json` file.
+// The Allure report groups the failed tests into categories by their status, message and trace.
+// The test matches the category if all set matchers match it.
+type Category struct {
+	Name            string   `json:"name"`                      // Name of the category
+	MessageRegex    string   `json:"messageRegex,omitempty"`    // Regular expression the whole status message must match
+	TraceRegex      string   `json:"traceRegex,omitempty"`      // Regular expression the whole status trace must match
+	MatchedStatuses []Status `json:"matchedStatuses,omitempty"` // Statuses of the test, any status matches if empty
+	Flaky           bool     `json:"flaky,omitempty"`           // Only flaky tests match the category if true
+
+	sink Sink
+}
+
+// NewCategory - Constructor. Builds and returns a new `allure.Category` object with passed name.
+func NewCategory(name string) *Category {
+	return &Category{Name: name}
+}
+
+// WithMessageRegex sets the regular expression the status message must match.
+// Returns a pointer to the current `allure.Category` (for Fluent Interface).
+func (category *Category) WithMessageRegex(regex string) *Category {
+	category.MessageRegex = regex
+	return category
+}
+
+// WithTraceRegex sets the regular expression the status trace must match.
+// Returns a pointer to the current `allure.Category` (for Fluent Interface).
+func (category *Category) WithTraceRegex(regex string) *Category {
+	category.TraceRegex = regex
+	return category
+}
+
+// WithStatuses adds the statuses the test must have.
+// Returns a pointer to the current `allure.Category` (for Fluent Interface).
+func (category *Category) WithStatuses(statuses ...Status) *Category {
+	category.MatchedStatuses = append(category.MatchedStatuses, statuses...)
+	return category
+}
+
+// WithFlaky makes only the flaky tests match the category.
+// Returns a pointer to the current `allure.Category` (for Fluent Interface).
+func (category *Category) WithFlaky() *Category {
+	category.Flaky = true
+	return category
+}
+
+// WithSink sets the Sink the category will be printed to.
+// If sink is nil, the process-wide Sink is used (see SetSink).
+// Returns a pointer to the current `allure.Category` (for Fluent Interface).
+func (category *Category) WithSink(sink Sink) *Category {
+	category.sink = sink
+	return category
+}
+
+// Print merges the category into `categories.json` of the category's Sink.
+// The categories already written (e.g. by other test packages of the launch) are kept, the one with the same name is replaced.
+// Returns error if the category has no name or its regular expressions are invalid.
+func (category *Category) Print() error {
+	if err := category.check(); err != nil {
+		return err
+	}
+
+	return mergeFile(sinkOrDefault(category.sink), categoriesFileName, func(existing []byte) ([]byte, error) {
+		var categories []*Category
+		if len(existing) > 0 {
+			if err := json.Unmarshal(existing, &categories); err != nil {
+				return nil, errors.Wrapf(err, "Failed unmarshal %s", categoriesFileName)
+			}
+		}
+
+		replaced := false
+		for i, existingCategory := range categories {
+			if existingCategory.Name == category.Name {
+				categories[i] = category
+				replaced = true
+			}
+		}
+
+		if !replaced {
+			categories = append(categories, category)
+		}
+
+		return json.MarshalIndent(categories, "", "  ")
+	})
+}
+
+func (category *Category) check() error {
+	if category.Name == "" {
+		return errors.New("Category name is empty")
+	}
+
+	for _, regex := range []string{category.MessageRegex, category.TraceRegex} {
+		if _, err := regexp.Compile(regex); err != nil {
+			return errors.Wrapf(err, "Category %q has invalid regex", category.Name)
+		}
+	}
+
+	return nil
+}
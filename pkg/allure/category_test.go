@@ -0,0 +1,40 @@
+package allure
+
+import (
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategory_Print(t *testing.T) {
+	sink := NewMemorySink()
+
+	timeouts := NewCategory("Timeouts").WithMessageRegex(".*deadline exceeded.*").WithStatuses(Broken, Failed).WithSink(sink)
+	require.NoError(t, timeouts.Print())
+	require.NoError(t, NewCategory("Flaky").WithFlaky().WithSink(sink).Print())
+	require.NoError(t, NewCategory("Timeouts").WithTraceRegex(".*context.go.*").WithSink(sink).Print())
+
+	content, ok := sink.File(categoriesFileName)
+	require.True(t, ok)
+
+	var categories []*Category
+	require.NoError(t, json.Unmarshal(content, &categories))
+	require.Len(t, categories, 2)
+
+	require.Equal(t, "Timeouts", categories[0].Name)
+	require.Empty(t, categories[0].MessageRegex)
+	require.Equal(t, ".*context.go.*", categories[0].TraceRegex)
+	require.Empty(t, categories[0].MatchedStatuses)
+
+	require.Equal(t, "Flaky", categories[1].Name)
+	require.True(t, categories[1].Flaky)
+}
+
+func TestCategory_PrintInvalid(t *testing.T) {
+	sink := NewMemorySink()
+
+	require.EqualError(t, NewCategory("").WithSink(sink).Print(), "Category name is empty")
+	require.Error(t, NewCategory("Invalid").WithMessageRegex("(").WithSink(sink).Print())
+	require.Empty(t, sink.Files())
+}
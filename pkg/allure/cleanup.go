@@ -0,0 +1,287 @@
+package allure
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// CleanupMode defines what happens with the results of the earlier launches when a new launch opens the results directory
+type CleanupMode int
+
+// CleanupMode constants
+const (
+	CleanupAppend CleanupMode = iota // The new results are added to the old ones
+	CleanupClean                     // The old results are removed
+	CleanupRotate                    // The old results are moved to the `launch-<time>` subfolder, the oldest subfolders are removed
+)
+
+// String returns the name of the mode as it is set in `$ALLURE_CLEANUP`
+func (mode CleanupMode) String() string {
+	switch mode {
+	case CleanupClean:
+		return "clean"
+	case CleanupRotate:
+		return "rotate"
+	default:
+		return "append"
+	}
+}
+
+// DefaultKeepLaunches is the number of the rotated launches kept if CleanupPolicy.Keep is not set
+const DefaultKeepLaunches = 5
+
+const (
+	launchMarkerFileName = ".allure-launch" // the file with the ID of the launch the results directory was cleaned for
+	launchFolderPrefix   = "launch-"        // the prefix of the subfolders with the rotated results
+	launchFolderLayout   = "20060102-150405.000000"
+)
+
+// CleanupPolicy defines what is done with the results directory once per launch, before the first file is written to it.
+// The launch is identified by `$ALLURE_LAUNCH_ID`, or by the `go test` process that runs the test binaries of all packages,
+// so the policy is applied once even if the packages are tested in parallel.
+type CleanupPolicy struct {
+	Mode CleanupMode
+	Keep int // Number of the rotated launches kept by CleanupRotate, DefaultKeepLaunches if not positive
+}
+
+func (policy CleanupPolicy) keep() int {
+	if policy.Keep > 0 {
+		return policy.Keep
+	}
+
+	return DefaultKeepLaunches
+}
+
+var (
+	cleanupMu     sync.RWMutex
+	cleanupPolicy = cleanupPolicyFromEnv()
+)
+
+// SetCleanupPolicy sets the process-wide CleanupPolicy of the results directories.
+// By default, it is taken from `$ALLURE_CLEANUP` (`append`, `clean` or `rotate`) and `$ALLURE_KEEP_LAUNCHES`,
+// `append` if it is not set.
+func SetCleanupPolicy(policy CleanupPolicy) {
+	cleanupMu.Lock()
+	defer cleanupMu.Unlock()
+
+	cleanupPolicy = policy
+}
+
+// GetCleanupPolicy returns the process-wide CleanupPolicy
+func GetCleanupPolicy() CleanupPolicy {
+	cleanupMu.RLock()
+	defer cleanupMu.RUnlock()
+
+	return cleanupPolicy
+}
+
+func cleanupPolicyFromEnv() CleanupPolicy {
+	var policy CleanupPolicy
+
+	switch strings.ToLower(strings.TrimSpace(configValue(cleanupEnvKey))) {
+	case "clean":
+		policy.Mode = CleanupClean
+	case "rotate", "keep":
+		policy.Mode = CleanupRotate
+	default:
+		policy.Mode = CleanupAppend
+	}
+
+	policy.Keep, _ = strconv.Atoi(strings.TrimSpace(configValue(keepLaunchesEnvKey)))
+
+	return policy
+}
+
+var (
+	// processLaunchID is the ID of the launch the process belongs to unless `$ALLURE_LAUNCH_ID` is set
+	processLaunchID = defaultLaunchID()
+
+	// preparedDirs are the sync.Once of the results directories the CleanupPolicy is applied to by the process
+	preparedDirs sync.Map
+)
+
+// LaunchID returns the ID of the current launch: `$ALLURE_LAUNCH_ID` (`launchID` of the config file),
+// or the name of the work directory of the `go` command the test binary is run from, it is shared by all packages
+// of one `go test` command. The test binary run directly (e.g. built by `go test -c`) is the launch of its own.
+func LaunchID() string {
+	if id := configValue(launchIDEnvKey); id != "" {
+		return id
+	}
+
+	return processLaunchID
+}
+
+// defaultLaunchID returns the `go-build*` work directory of the test binary, or the unique ID of the process
+func defaultLaunchID() string {
+	if executable, err := os.Executable(); err == nil {
+		for dir := filepath.Dir(executable); dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+			if name := filepath.Base(dir); strings.HasPrefix(name, "go-build") {
+				return name
+			}
+		}
+	}
+
+	return fmt.Sprintf("run-%d-%d", os.Getpid(), time.Now().UnixNano())
+}
+
+// prepareResultsDir creates the results directory and applies the CleanupPolicy to it if the current launch hasn't done it yet.
+// The policy is applied once per directory by the process, the processes of the launch are serialized by the lock file
+// in the directory. The cleanup errors are printed, only the error of the directory creation is returned.
+func prepareResultsDir(dir string) error {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return errors.Wrap(err, "Cannot create results directory")
+	}
+
+	policy := GetCleanupPolicy()
+	if policy.Mode == CleanupAppend {
+		return nil
+	}
+
+	key := dir
+	if abs, err := filepath.Abs(dir); err == nil {
+		key = abs
+	}
+
+	once, _ := preparedDirs.LoadOrStore(key, new(sync.Once))
+	once.(*sync.Once).Do(func() {
+		if err := cleanupResultsDir(dir, policy, LaunchID()); err != nil {
+			fmt.Printf("Cannot %s results directory %s: %s\n", policy.Mode, dir, err)
+		}
+	})
+
+	return nil
+}
+
+func cleanupResultsDir(dir string, policy CleanupPolicy, launchID string) error {
+	unlock, err := lockFile(filepath.Join(dir, launchMarkerFileName+".lock"))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	marker := filepath.Join(dir, launchMarkerFileName)
+
+	previous, err := os.ReadFile(filepath.Clean(marker))
+	if err == nil && string(previous) == launchID {
+		return nil
+	}
+
+	// the rotated results are named by the time of the launch they belong to
+	launchTime := time.Now()
+	if info, statErr := os.Stat(marker); statErr == nil {
+		launchTime = info.ModTime()
+	}
+
+	switch policy.Mode {
+	case CleanupClean:
+		err = removeLaunchFiles(dir)
+	case CleanupRotate:
+		err = rotateLaunchFiles(dir, launchTime, policy.keep())
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(marker, []byte(launchID))
+}
+
+// launchFiles returns the names of the files of the results directory left by the previous launch
+func launchFiles(dir string, withRotated bool) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		switch {
+		case name == launchMarkerFileName, strings.HasSuffix(name, ".lock"):
+			continue
+		case !withRotated && entry.IsDir() && strings.HasPrefix(name, launchFolderPrefix):
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+func removeLaunchFiles(dir string) error {
+	names, err := launchFiles(dir, true)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, name := range names {
+		if err = os.RemoveAll(filepath.Join(dir, name)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return joinErrors(errs)
+}
+
+// rotateLaunchFiles moves the results of the previous launch to the `launch-<time>` subfolder
+// and removes the oldest subfolders, so only keep of them are left
+func rotateLaunchFiles(dir string, launchTime time.Time, keep int) error {
+	names, err := launchFiles(dir, false)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	if len(names) > 0 {
+		launchDir := filepath.Join(dir, launchFolderPrefix+launchTime.UTC().Format(launchFolderLayout))
+		if err = os.MkdirAll(launchDir, os.ModePerm); err != nil {
+			return errors.Wrap(err, "Cannot create launch directory")
+		}
+
+		for _, name := range names {
+			if err = os.Rename(filepath.Join(dir, name), filepath.Join(launchDir, name)); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return joinErrors(append(errs, err))
+	}
+
+	var launches []string
+
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), launchFolderPrefix) {
+			launches = append(launches, entry.Name())
+		}
+	}
+
+	// the names are sorted by the launch time
+	sort.Strings(launches)
+
+	for len(launches) > keep {
+		if err = os.RemoveAll(filepath.Join(dir, launches[0])); err != nil {
+			errs = append(errs, err)
+		}
+
+		launches = launches[1:]
+	}
+
+	return joinErrors(errs)
+}
@@ -0,0 +1,151 @@
+package allure
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeLaunchResult(t *testing.T, dir, name string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("{}"), fileSystemPermissionCode))
+}
+
+func launchDirs(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	var dirs []string
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), launchFolderPrefix) {
+			dirs = append(dirs, entry.Name())
+		}
+	}
+
+	return dirs
+}
+
+func TestCleanupResultsDir_clean(t *testing.T) {
+	dir := t.TempDir()
+	writeLaunchResult(t, dir, "old-result.json")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, launchFolderPrefix+"old"), os.ModePerm))
+
+	policy := CleanupPolicy{Mode: CleanupClean}
+	require.NoError(t, cleanupResultsDir(dir, policy, "first"))
+	require.NoFileExists(t, filepath.Join(dir, "old-result.json"))
+	require.NoDirExists(t, filepath.Join(dir, launchFolderPrefix+"old"))
+
+	// the same launch doesn't clean the results of its other processes
+	writeLaunchResult(t, dir, "new-result.json")
+	require.NoError(t, cleanupResultsDir(dir, policy, "first"))
+	require.FileExists(t, filepath.Join(dir, "new-result.json"))
+
+	require.NoError(t, cleanupResultsDir(dir, policy, "second"))
+	require.NoFileExists(t, filepath.Join(dir, "new-result.json"))
+
+	marker, err := os.ReadFile(filepath.Join(dir, launchMarkerFileName))
+	require.NoError(t, err)
+	require.Equal(t, "second", string(marker))
+}
+
+func TestCleanupResultsDir_rotate(t *testing.T) {
+	dir := t.TempDir()
+	policy := CleanupPolicy{Mode: CleanupRotate, Keep: 2}
+
+	for i, launch := range []string{"first", "second", "third", "fourth"} {
+		require.NoError(t, cleanupResultsDir(dir, policy, launch))
+		writeLaunchResult(t, dir, launch+"-result.json")
+
+		if i == 0 {
+			require.Empty(t, launchDirs(t, dir))
+		}
+	}
+
+	dirs := launchDirs(t, dir)
+	require.Len(t, dirs, 2)
+	require.FileExists(t, filepath.Join(dir, dirs[0], "second-result.json"))
+	require.FileExists(t, filepath.Join(dir, dirs[1], "third-result.json"))
+	require.FileExists(t, filepath.Join(dir, "fourth-result.json"))
+	require.NoFileExists(t, filepath.Join(dir, "third-result.json"))
+}
+
+func TestCleanupResultsDir_concurrent(t *testing.T) {
+	dir := t.TempDir()
+	writeLaunchResult(t, dir, "old-result.json")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, cleanupResultsDir(dir, CleanupPolicy{Mode: CleanupRotate}, "launch"))
+		}()
+	}
+	wg.Wait()
+
+	dirs := launchDirs(t, dir)
+	require.Len(t, dirs, 1)
+	require.FileExists(t, filepath.Join(dir, dirs[0], "old-result.json"))
+}
+
+func TestFileSink_cleanup(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "allure-results")
+	require.NoError(t, os.MkdirAll(dir, os.ModePerm))
+	writeLaunchResult(t, dir, "old-result.json")
+
+	SetCleanupPolicy(CleanupPolicy{Mode: CleanupClean})
+	defer SetCleanupPolicy(CleanupPolicy{})
+
+	require.NoError(t, NewFileSink(dir).CreateFile("new-result.json", []byte("{}")))
+	require.NoFileExists(t, filepath.Join(dir, "old-result.json"))
+	require.FileExists(t, filepath.Join(dir, "new-result.json"))
+}
+
+func TestPlainFileSink_noCleanup(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "allure-results")
+	require.NoError(t, os.MkdirAll(dir, os.ModePerm))
+	writeLaunchResult(t, dir, "old-result.json")
+
+	SetCleanupPolicy(CleanupPolicy{Mode: CleanupClean})
+	defer SetCleanupPolicy(CleanupPolicy{})
+
+	require.NoError(t, NewPlainFileSink(dir).CreateFile("new-result.json", []byte("{}")))
+	require.FileExists(t, filepath.Join(dir, "old-result.json"))
+	require.FileExists(t, filepath.Join(dir, "new-result.json"))
+	require.NoFileExists(t, filepath.Join(dir, launchMarkerFileName))
+}
+
+func TestCleanupPolicyFromEnv(t *testing.T) {
+	require.Equal(t, CleanupPolicy{Mode: CleanupAppend}, cleanupPolicyFromEnv())
+
+	t.Setenv(cleanupEnvKey, "rotate")
+	t.Setenv(keepLaunchesEnvKey, "3")
+	require.Equal(t, CleanupPolicy{Mode: CleanupRotate, Keep: 3}, cleanupPolicyFromEnv())
+	require.Equal(t, DefaultKeepLaunches, CleanupPolicy{}.keep())
+
+	// the test binary is run by `go test` from its work directory
+	require.True(t, strings.HasPrefix(LaunchID(), "go-build"), LaunchID())
+
+	t.Setenv(launchIDEnvKey, "ci-42")
+	require.Equal(t, "ci-42", LaunchID())
+}
+
+func TestPrepareResultsDir_once(t *testing.T) {
+	SetCleanupPolicy(CleanupPolicy{Mode: CleanupClean})
+	defer SetCleanupPolicy(CleanupPolicy{})
+
+	dir := t.TempDir()
+	require.NoError(t, prepareResultsDir(dir))
+
+	// the marker of another launch is not checked again: the process has already prepared the directory
+	require.NoError(t, os.WriteFile(filepath.Join(dir, launchMarkerFileName), []byte("other"), fileSystemPermissionCode))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "old-result.json"), []byte("{}"), fileSystemPermissionCode))
+	require.NoError(t, prepareResultsDir(dir))
+	require.FileExists(t, filepath.Join(dir, "old-result.json"))
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ozontech/allure-go/pkg/allure"
+	"github.com/ozontech/allure-go/pkg/allure/test2json"
+)
+
+type convertFlags struct {
+	output string
+	echo   bool
+}
+
+func init() {
+	opts := new(convertFlags)
+
+	register(&command{
+		name:    "convert",
+		summary: "Converts `go test -json` events to allure results.",
+		usage:   "-o <dir> [-echo] [<events.json>...]",
+		flags: func(flags *flag.FlagSet) {
+			flags.StringVar(&opts.output, "o", "allure-results", "output directory")
+			flags.BoolVar(&opts.echo, "echo", false, "copy the output of the tests to the standard output")
+		},
+		run: func(flags *flag.FlagSet, args []string, stdout io.Writer) error {
+			return runConvert(opts, args, os.Stdin, stdout)
+		},
+	})
+}
+
+// runConvert converts the events of the files or of the standard input if there are no files
+func runConvert(opts *convertFlags, files []string, stdin io.Reader, stdout io.Writer) error {
+	// the results are written as is: the cleanup policy of the launch is applied by the tests themselves
+	converter := test2json.NewConverter(allure.NewPlainFileSink(opts.output))
+	if opts.echo {
+		converter.Echo = stdout
+	}
+
+	if len(files) == 0 {
+		if err := converter.Convert(stdin); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range files {
+		if err := convertFile(converter, name); err != nil {
+			return err
+		}
+	}
+
+	summary := converter.Summary()
+	fmt.Fprintf(stdout, "Converted %d results to %s: %d passed, %d failed, %d broken, %d skipped\n",
+		summary.Total(), opts.output, summary.Passed, summary.Failed, summary.Broken, summary.Skipped)
+
+	return nil
+}
+
+func convertFile(converter *test2json.Converter, name string) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return converter.Convert(file)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ozontech/allure-go/pkg/allure/junit"
+	"github.com/ozontech/allure-go/pkg/allure/results"
+)
+
+type junitFlags struct {
+	output string
+}
+
+func init() {
+	opts := new(junitFlags)
+
+	register(&command{
+		name:    "junit",
+		summary: "Exports an allure-results directory to the JUnit XML report.",
+		usage:   "[-o <report.xml>] <dir>",
+		flags: func(flags *flag.FlagSet) {
+			flags.StringVar(&opts.output, "o", "-", "output file, `-` is the standard output")
+		},
+		run: func(flags *flag.FlagSet, args []string, stdout io.Writer) error {
+			return runJUnit(opts, args, stdout)
+		},
+	})
+}
+
+func runJUnit(opts *junitFlags, args []string, stdout io.Writer) (err error) {
+	if len(args) != 1 {
+		return errors.New("exactly one results directory is expected")
+	}
+
+	launch, readErr := results.Read(args[0])
+	if launch == nil {
+		return readErr
+	}
+
+	output := stdout
+
+	if opts.output != "-" {
+		file, err := os.Create(opts.output)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+
+		output = file
+	}
+
+	if err = junit.Write(output, junit.ConvertLaunch(launch)); err != nil {
+		return err
+	}
+
+	return describeReadError(readErr)
+}
+
+// describeReadError tells the skipped results and containers from the attachments missing their files,
+// the report is written in both cases
+func describeReadError(readErr error) error {
+	var errs *results.Error
+	if !errors.As(readErr, &errs) {
+		return readErr
+	}
+
+	var skipped, missing []string
+
+	for _, err := range errs.Errs {
+		var fileErr *results.FileError
+		if errors.As(err, &fileErr) && !strings.HasSuffix(fileErr.Name, results.ResultFileSuffix) &&
+			!strings.HasSuffix(fileErr.Name, results.ContainerFileSuffix) {
+			missing = append(missing, err.Error())
+			continue
+		}
+
+		skipped = append(skipped, err.Error())
+	}
+
+	var messages []string
+	if len(skipped) > 0 {
+		messages = append(messages, "some results are skipped:\n"+strings.Join(skipped, "\n"))
+	}
+
+	if len(missing) > 0 {
+		messages = append(messages, "some attachments are not found:\n"+strings.Join(missing, "\n"))
+	}
+
+	if len(messages) == 0 {
+		return nil
+	}
+
+	return errors.New(strings.Join(messages, "\n"))
+}
@@ -0,0 +1,113 @@
+// Command allure-go post-processes allure-results directories.
+//
+// Usage:
+//
+//	allure-go <command> [flags] [arguments]
+//
+// Run `allure-go help <command>` for the flags of the command.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// command is a subcommand of allure-go
+type command struct {
+	name    string
+	summary string
+	usage   string
+	run     func(flags *flag.FlagSet, args []string, stdout io.Writer) error
+	flags   func(flags *flag.FlagSet)
+}
+
+var commands = map[string]*command{}
+
+func register(cmd *command) {
+	commands[cmd.name] = cmd
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command and returns the exit code: 0 on success, 1 on failure and 2 on wrong usage
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		printUsage(stderr)
+		return 2
+	}
+
+	name, args := args[0], args[1:]
+
+	if name == "help" || name == "-h" || name == "--help" {
+		if len(args) > 0 {
+			if cmd, ok := commands[args[0]]; ok {
+				newFlagSet(cmd, stdout).Usage()
+				return 0
+			}
+		}
+
+		printUsage(stdout)
+
+		return 0
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "allure-go: unknown command %q\n", name)
+		printUsage(stderr)
+
+		return 2
+	}
+
+	flags := newFlagSet(cmd, stderr)
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+
+		return 2
+	}
+
+	if err := cmd.run(flags, flags.Args(), stdout); err != nil {
+		fmt.Fprintf(stderr, "allure-go %s: %s\n", cmd.name, err)
+		return 1
+	}
+
+	return 0
+}
+
+func newFlagSet(cmd *command, output io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	flags.SetOutput(output)
+	flags.Usage = func() {
+		fmt.Fprintf(output, "Usage: allure-go %s %s\n\n%s\n\nFlags:\n", cmd.name, cmd.usage, cmd.summary)
+		flags.PrintDefaults()
+	}
+
+	if cmd.flags != nil {
+		cmd.flags(flags)
+	}
+
+	return flags
+}
+
+func printUsage(output io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(output, "Usage: allure-go <command> [flags] [arguments]\n\nCommands:\n")
+
+	for _, name := range names {
+		fmt.Fprintf(output, "  %-10s %s\n", name, commands[name].summary)
+	}
+
+	fmt.Fprintf(output, "\nRun `allure-go help <command>` for the flags of the command.\n")
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ozontech/allure-go/pkg/allure"
+)
+
+func runCommand(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+
+	code := run(args, &stdout, &stderr)
+
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun_Usage(t *testing.T) {
+	code, _, stderr := runCommand()
+	require.Equal(t, 2, code)
+	require.Contains(t, stderr, "merge")
+
+	code, _, stderr = runCommand("unknown")
+	require.Equal(t, 2, code)
+	require.Contains(t, stderr, `unknown command "unknown"`)
+
+	code, stdout, _ := runCommand("help", "merge")
+	require.Equal(t, 0, code)
+	require.Contains(t, stdout, "-retries")
+
+	code, _, _ = runCommand("merge", "-unknown")
+	require.Equal(t, 2, code)
+}
+
+func TestRun_Merge(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(t.TempDir(), "merged")
+
+	require.NoError(t, allure.NewResult("Test", "pkg/Test").WithSink(allure.NewFileSink(dir)).Print())
+
+	code, stdout, stderr := runCommand("merge", "-o", output, "-retries", "collapse", dir, dir)
+	require.Equal(t, 0, code, stderr)
+	require.Contains(t, stdout, "1 results")
+
+	code, _, stderr = runCommand("merge", "-o", output, dir)
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, "not empty")
+
+	code, _, stderr = runCommand("merge", "-o", output, "-clean", "-retries", "drop", dir)
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, `unknown retries mode "drop"`)
+
+	code, _, stderr = runCommand("merge", dir)
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, "-o")
+}
+
+func TestRun_JUnit(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(t.TempDir(), "report.xml")
+
+	result := allure.NewResult("Test", "pkg/Test").WithSuite("Suite").WithSink(allure.NewFileSink(dir))
+	result.Status = allure.Failed
+	require.NoError(t, result.Print())
+
+	code, stdout, stderr := runCommand("junit", dir)
+	require.Equal(t, 0, code, stderr)
+	require.Contains(t, stdout, `<testsuite name="Suite" tests="1" failures="1"`)
+
+	code, _, stderr = runCommand("junit", "-o", output, dir)
+	require.Equal(t, 0, code, stderr)
+
+	content, err := os.ReadFile(output)
+	require.NoError(t, err)
+	require.Contains(t, string(content), `<testcase name="Test" classname="Suite"`)
+
+	code, _, stderr = runCommand("junit", dir, dir)
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, "exactly one")
+}
+
+func TestRun_JUnit_readErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	result := allure.NewResult("Test", "pkg/Test").WithSink(allure.NewFileSink(dir))
+	result.Attachments = append(result.Attachments, allure.NewAttachment("log", allure.Text, []byte("content")))
+	require.NoError(t, result.Print())
+
+	attachments, err := filepath.Glob(filepath.Join(dir, "*-attachment*"))
+	require.NoError(t, err)
+	require.Len(t, attachments, 1)
+	require.NoError(t, os.Remove(attachments[0]))
+
+	code, stdout, stderr := runCommand("junit", dir)
+	require.Equal(t, 1, code)
+	require.Contains(t, stdout, `<testcase name="Test"`)
+	require.Contains(t, stderr, "some attachments are not found")
+	require.NotContains(t, stderr, "some results are skipped")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken-result.json"), []byte("{"), 0o600))
+
+	code, _, stderr = runCommand("junit", dir)
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, "some results are skipped:\nbroken-result.json")
+	require.Contains(t, stderr, "some attachments are not found")
+}
+
+func TestRun_Convert(t *testing.T) {
+	events := filepath.Join(t.TempDir(), "events.json")
+	output := filepath.Join(t.TempDir(), "allure-results")
+
+	require.NoError(t, os.WriteFile(events, []byte(`{"Action":"run","Package":"example/pkg","Test":"TestFailed"}
+{"Action":"output","Package":"example/pkg","Test":"TestFailed","Output":"    pkg_test.go:10: whoops\n"}
+{"Action":"fail","Package":"example/pkg","Test":"TestFailed"}
+{"Action":"fail","Package":"example/pkg"}
+`), 0o644))
+
+	code, stdout, stderr := runCommand("convert", "-o", output, "-echo", events)
+	require.Equal(t, 0, code, stderr)
+	require.Contains(t, stdout, "pkg_test.go:10: whoops")
+	require.Contains(t, stdout, "Converted 1 results")
+
+	code, _, _ = runCommand("convert", "-o", output, filepath.Join(t.TempDir(), "not-exist.json"))
+	require.Equal(t, 1, code)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/ozontech/allure-go/pkg/allure/results"
+)
+
+type mergeFlags struct {
+	output  string
+	retries string
+	clean   bool
+}
+
+func init() {
+	opts := new(mergeFlags)
+
+	register(&command{
+		name:    "merge",
+		summary: "Merges allure-results directories into one.",
+		usage:   "-o <output> [-retries keep|collapse] [-clean] <dir>...",
+		flags: func(flags *flag.FlagSet) {
+			flags.StringVar(&opts.output, "o", "", "output directory (required)")
+			flags.StringVar(&opts.retries, "retries", "keep", "`mode` of the results of the same test: keep all of them or collapse to the latest one")
+			flags.BoolVar(&opts.clean, "clean", false, "remove the output directory before merging")
+		},
+		run: func(flags *flag.FlagSet, args []string, stdout io.Writer) error {
+			return runMerge(opts, args, stdout)
+		},
+	})
+}
+
+func runMerge(opts *mergeFlags, dirs []string, stdout io.Writer) error {
+	if opts.output == "" {
+		return errors.New("output directory is required (-o)")
+	}
+
+	if len(dirs) == 0 {
+		return errors.New("no directories to merge")
+	}
+
+	mergeOpts := results.MergeOptions{Clean: opts.clean}
+
+	switch opts.retries {
+	case "keep":
+		mergeOpts.Retries = results.KeepRetries
+	case "collapse":
+		mergeOpts.Retries = results.CollapseRetries
+	default:
+		return fmt.Errorf("unknown retries mode %q", opts.retries)
+	}
+
+	report, err := results.Merge(opts.output, dirs, mergeOpts)
+	if report != nil {
+		fmt.Fprintf(stdout, "Merged %d directories into %s: %d results, %d containers, %d attachments (%d duplicates skipped, %d retries collapsed, %d attachments renamed)\n",
+			len(dirs), opts.output, report.Results, report.Containers, report.Attachments, report.Duplicates, report.Collapsed, report.Renamed)
+	}
+
+	return err
+}
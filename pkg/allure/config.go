@@ -10,7 +10,44 @@ const (
 	testCasePatternEnvKey = "ALLURE_TESTCASE_PATTERN" // Indicates the URL pattern for TestCase. It must contain exactly one `%s`
 	tmsLinkPatternEnvKey  = "ALLURE_LINK_TMS_PATTERN" // Indicates the URL pattern for TmsLink. It must contain exactly one `%s`
 	defaultTagsEnvKey     = "ALLURE_LAUNCH_TAGS"      // Indicates the default tags that will mark all tests in the run. The tags must be specified separated by commas.
+	validationEnvKey      = "ALLURE_VALIDATION"       // Indicates the validation mode of the printed results: off, lenient or strict
+	idStrategyEnvKey      = "ALLURE_ID_STRATEGY"      // Indicates the strategy of the test case and history IDs: fullname, parameters or allure_id
+	redactEnvEnvKey       = "ALLURE_REDACT_ENV"       // Indicates the environment variables whose values are redacted from the results. The names must be specified separated by commas.
+	configPathEnvKey      = "ALLURE_CONFIG_PATH"      // Indicates the path to the allure-go config file
+	retriesEnvKey         = "ALLURE_RETRIES"          // Indicates the number of retries of the failed tests
+	markFlakyEnvKey       = "ALLURE_MARK_FLAKY"       // Indicates if the tests passed on retry are marked flaky: true or false
+	timeoutEnvKey         = "ALLURE_TIMEOUT"          // Indicates the timeout of each test, e.g. 30s
+	orderEnvKey           = "ALLURE_ORDER"            // Indicates the order of the tests of a suite: alphabetical, priority or shuffle
+	seedEnvKey            = "ALLURE_SEED"             // Indicates the seed the tests are shuffled with
+	filterEnvKey          = "ALLURE_FILTER"           // Indicates the regular expression to select the tests to run
+	labelFilterEnvKey     = "ALLURE_LABELS"           // Indicates the label expression to select the tests to run, e.g. `tag=smoke && !tag=slow`
+	testPlanPathEnvKey    = "ALLURE_TESTPLAN_PATH"    // Indicates the path to the test plan file: JSON or YAML
+	testPlanEnvKey        = "ALLURE_TESTPLAN"         // Indicates the inline test plan: the content of the test plan file
+	muteListPathEnvKey    = "ALLURE_MUTE_LIST_PATH"   // Indicates the path to the mute list file
+	cleanupEnvKey         = "ALLURE_CLEANUP"          // Indicates what is done with the results of the earlier launches: append, clean or rotate
+	keepLaunchesEnvKey    = "ALLURE_KEEP_LAUNCHES"    // Indicates the number of the launches kept by the rotate cleanup
+	launchIDEnvKey        = "ALLURE_LAUNCH_ID"        // Indicates the ID of the launch shared by all test processes
+)
+
+// Prefixes and suffixes of the environment variables with the name of the setting
+const (
+	linkEnvPrefix    = "ALLURE_LINK_"  // `ALLURE_LINK_<NAME>_PATTERN` indicates the URL pattern of the link type
+	patternEnvSuffix = "_PATTERN"      // `ALLURE_LINK_<NAME>_PATTERN` indicates the URL pattern of the link type
+	labelEnvPrefix   = "ALLURE_LABEL_" // `ALLURE_LABEL_<NAME>` indicates the default label of every result
 )
 
 // Attachment permission
 const fileSystemPermissionCode = 0o644
+
+// Output files suffixes
+const (
+	resultFileSuffix    = "-result.json"
+	containerFileSuffix = "-container.json"
+)
+
+// Launch files names
+const (
+	environmentFileName = "environment.properties"
+	executorFileName    = "executor.json"
+	categoriesFileName  = "categories.json"
+)
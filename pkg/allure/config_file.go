@@ -0,0 +1,380 @@
+package allure
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileNames are the names of the config file looked up from the working directory up to the module root
+var ConfigFileNames = []string{"allure-go.yaml", "allure-go.yml", "allure-go.json"}
+
+// Config is the allure-go configuration. It is read from the config file (see ConfigFileNames),
+// the environment variables of the same settings take precedence over the file.
+type Config struct {
+	OutputPath      string            `json:"outputPath,omitempty" yaml:"outputPath,omitempty"`           // $ALLURE_OUTPUT_PATH
+	OutputFolder    string            `json:"outputFolder,omitempty" yaml:"outputFolder,omitempty"`       // $ALLURE_OUTPUT_FOLDER
+	IssuePattern    string            `json:"issuePattern,omitempty" yaml:"issuePattern,omitempty"`       // $ALLURE_ISSUE_PATTERN
+	TestCasePattern string            `json:"testCasePattern,omitempty" yaml:"testCasePattern,omitempty"` // $ALLURE_TESTCASE_PATTERN
+	TmsLinkPattern  string            `json:"tmsLinkPattern,omitempty" yaml:"tmsLinkPattern,omitempty"`   // $ALLURE_LINK_TMS_PATTERN
+	LinkPatterns    map[string]string `json:"linkPatterns,omitempty" yaml:"linkPatterns,omitempty"`       // Patterns of the link types by name, $ALLURE_LINK_<NAME>_PATTERN
+	LaunchTags      []string          `json:"launchTags,omitempty" yaml:"launchTags,omitempty"`           // $ALLURE_LAUNCH_TAGS
+	Labels          map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`                   // Default labels of every result by name, $ALLURE_LABEL_<NAME>
+	Validation      string            `json:"validation,omitempty" yaml:"validation,omitempty"`           // $ALLURE_VALIDATION
+	IDStrategy      string            `json:"idStrategy,omitempty" yaml:"idStrategy,omitempty"`           // $ALLURE_ID_STRATEGY
+	Redaction       RedactionConfig   `json:"redaction,omitempty" yaml:"redaction,omitempty"`
+	Retries         int               `json:"retries,omitempty" yaml:"retries,omitempty"`           // $ALLURE_RETRIES
+	MarkFlaky       bool              `json:"markFlaky,omitempty" yaml:"markFlaky,omitempty"`       // $ALLURE_MARK_FLAKY
+	Timeout         string            `json:"timeout,omitempty" yaml:"timeout,omitempty"`           // Timeout of each test (e.g. `30s`), $ALLURE_TIMEOUT
+	Order           string            `json:"order,omitempty" yaml:"order,omitempty"`               // $ALLURE_ORDER
+	Seed            int64             `json:"seed,omitempty" yaml:"seed,omitempty"`                 // $ALLURE_SEED
+	Filter          string            `json:"filter,omitempty" yaml:"filter,omitempty"`             // $ALLURE_FILTER
+	LabelFilter     string            `json:"labelFilter,omitempty" yaml:"labelFilter,omitempty"`   // Label expression selecting the tests, $ALLURE_LABELS
+	TestPlanPath    string            `json:"testPlanPath,omitempty" yaml:"testPlanPath,omitempty"` // $ALLURE_TESTPLAN_PATH
+	TestPlan        string            `json:"testPlan,omitempty" yaml:"testPlan,omitempty"`         // Inline test plan, $ALLURE_TESTPLAN
+	MuteListPath    string            `json:"muteListPath,omitempty" yaml:"muteListPath,omitempty"` // $ALLURE_MUTE_LIST_PATH
+	Cleanup         string            `json:"cleanup,omitempty" yaml:"cleanup,omitempty"`           // $ALLURE_CLEANUP
+	KeepLaunches    int               `json:"keepLaunches,omitempty" yaml:"keepLaunches,omitempty"` // $ALLURE_KEEP_LAUNCHES
+	LaunchID        string            `json:"launchID,omitempty" yaml:"launchID,omitempty"`         // $ALLURE_LAUNCH_ID
+
+	Path string `json:"-" yaml:"-"` // Path of the loaded config file, empty if there is no file
+}
+
+// RedactionConfig is the redaction section of the Config (see RedactValues, RedactPatterns and RedactEnv)
+type RedactionConfig struct {
+	Values   []string `json:"values,omitempty" yaml:"values,omitempty"`
+	Patterns []string `json:"patterns,omitempty" yaml:"patterns,omitempty"`
+	Env      []string `json:"env,omitempty" yaml:"env,omitempty"` // $ALLURE_REDACT_ENV
+}
+
+var (
+	configFileMu   sync.RWMutex
+	configFileOnce sync.Once
+	configFile     *Config
+)
+
+// LoadConfig reads the config file. The format is chosen by the extension: `.json` or YAML otherwise.
+func LoadConfig(path string) (*Config, error) {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read the allure-go config")
+	}
+
+	cfg := new(Config)
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(content, cfg)
+	} else {
+		err = yaml.Unmarshal(content, cfg)
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to parse the allure-go config %s", path)
+	}
+
+	cfg.Path = path
+
+	return cfg, nil
+}
+
+// SetConfigFile replaces the process-wide config file values, e.g. with the result of LoadConfig.
+// Passing nil discovers the config file again (see FindConfigFile).
+// The settings already applied on start (validation, ID strategy and redaction) are not changed.
+func SetConfigFile(cfg *Config) {
+	configFileOnce.Do(func() {})
+
+	configFileMu.Lock()
+	defer configFileMu.Unlock()
+
+	if cfg == nil {
+		cfg = loadConfigFile()
+	}
+
+	configFile = cfg
+}
+
+// GetConfig returns the effective config: the values of the config file overridden by the environment variables
+func GetConfig() *Config {
+	file := getConfigFile()
+
+	cfg := &Config{
+		OutputPath:      configValue(resultsPathEnvKey),
+		OutputFolder:    configValue(outputFolderEnvKey),
+		IssuePattern:    configValue(issuePatternEnvKey),
+		TestCasePattern: configValue(testCasePatternEnvKey),
+		TmsLinkPattern:  configValue(tmsLinkPatternEnvKey),
+		LinkPatterns:    make(map[string]string),
+		LaunchTags:      splitList(configValue(defaultTagsEnvKey)),
+		Labels:          defaultLabels(),
+		Validation:      configValue(validationEnvKey),
+		IDStrategy:      configValue(idStrategyEnvKey),
+		Redaction: RedactionConfig{
+			Values:   file.Redaction.Values,
+			Patterns: file.Redaction.Patterns,
+			Env:      splitList(configValue(redactEnvEnvKey)),
+		},
+		Filter:       configValue(filterEnvKey),
+		LabelFilter:  configValue(labelFilterEnvKey),
+		TestPlanPath: configValue(testPlanPathEnvKey),
+		TestPlan:     configValue(testPlanEnvKey),
+		MuteListPath: configValue(muteListPathEnvKey),
+		Timeout:      configValue(timeoutEnvKey),
+		Order:        configValue(orderEnvKey),
+		Cleanup:      configValue(cleanupEnvKey),
+		LaunchID:     configValue(launchIDEnvKey),
+		Path:         file.Path,
+	}
+
+	cfg.Retries, _ = strconv.Atoi(configValue(retriesEnvKey))
+	cfg.KeepLaunches, _ = strconv.Atoi(configValue(keepLaunchesEnvKey))
+	cfg.MarkFlaky, _ = strconv.ParseBool(configValue(markFlakyEnvKey))
+	cfg.Seed, _ = strconv.ParseInt(configValue(seedEnvKey), 10, 64)
+
+	for name := range file.LinkPatterns {
+		cfg.LinkPatterns[strings.ToLower(name)] = configValue(linkTypePatternEnvKey(name))
+	}
+
+	for _, env := range os.Environ() {
+		key, value := splitEnv(env)
+		if value == "" || key == tmsLinkPatternEnvKey ||
+			!strings.HasPrefix(key, linkEnvPrefix) || !strings.HasSuffix(key, patternEnvSuffix) {
+			continue
+		}
+
+		name := strings.TrimSuffix(strings.TrimPrefix(key, linkEnvPrefix), patternEnvSuffix)
+		if _, ok := cfg.LinkPatterns[strings.ToLower(name)]; !ok && name != "" {
+			cfg.LinkPatterns[strings.ToLower(name)] = value
+		}
+	}
+
+	return cfg
+}
+
+// FindConfigFile returns the path of the config file: `$ALLURE_CONFIG_PATH`, or the first of ConfigFileNames
+// found in the working directory or its parents up to the directory with `go.mod`.
+// Returns an empty string if there is no config file.
+func FindConfigFile() string {
+	if path := os.Getenv(configPathEnvKey); path != "" {
+		return path
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	for {
+		for _, name := range ConfigFileNames {
+			path := filepath.Join(dir, name)
+			if ok, _ := exists(path); ok {
+				return path
+			}
+		}
+
+		// stop looking if project root found
+		if ok, _ := exists(filepath.Join(dir, "go.mod")); ok {
+			return ""
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+
+		dir = parent
+	}
+}
+
+func loadConfigFile() *Config {
+	path := FindConfigFile()
+	if path == "" {
+		return new(Config)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		fmt.Printf("%s, it is ignored.\n", err)
+		return new(Config)
+	}
+
+	return cfg
+}
+
+func getConfigFile() *Config {
+	configFileOnce.Do(func() {
+		configFileMu.Lock()
+		defer configFileMu.Unlock()
+
+		configFile = loadConfigFile()
+	})
+
+	configFileMu.RLock()
+	defer configFileMu.RUnlock()
+
+	return configFile
+}
+
+// configValue returns the value of the environment variable, or the value of the same setting of the config file
+// if the variable is not set
+func configValue(envKey string) string {
+	if value := os.Getenv(envKey); value != "" {
+		return value
+	}
+
+	return getConfigFile().value(envKey)
+}
+
+// value returns the setting of the config file by the name of its environment variable
+func (cfg *Config) value(envKey string) string {
+	switch envKey {
+	case resultsPathEnvKey:
+		return cfg.OutputPath
+	case outputFolderEnvKey:
+		return cfg.OutputFolder
+	case issuePatternEnvKey:
+		return cfg.IssuePattern
+	case testCasePatternEnvKey:
+		return cfg.TestCasePattern
+	case tmsLinkPatternEnvKey:
+		return cfg.TmsLinkPattern
+	case defaultTagsEnvKey:
+		return strings.Join(cfg.LaunchTags, ",")
+	case validationEnvKey:
+		return cfg.Validation
+	case idStrategyEnvKey:
+		return cfg.IDStrategy
+	case redactEnvEnvKey:
+		return strings.Join(cfg.Redaction.Env, ",")
+	case retriesEnvKey:
+		return intValue(cfg.Retries)
+	case markFlakyEnvKey:
+		if cfg.MarkFlaky {
+			return strconv.FormatBool(cfg.MarkFlaky)
+		}
+
+		return ""
+	case timeoutEnvKey:
+		return cfg.Timeout
+	case orderEnvKey:
+		return cfg.Order
+	case seedEnvKey:
+		if cfg.Seed != 0 {
+			return strconv.FormatInt(cfg.Seed, 10)
+		}
+
+		return ""
+	case keepLaunchesEnvKey:
+		return intValue(cfg.KeepLaunches)
+	case cleanupEnvKey:
+		return cfg.Cleanup
+	case launchIDEnvKey:
+		return cfg.LaunchID
+	case filterEnvKey:
+		return cfg.Filter
+	case labelFilterEnvKey:
+		return cfg.LabelFilter
+	case testPlanPathEnvKey:
+		return cfg.TestPlanPath
+	case testPlanEnvKey:
+		return cfg.TestPlan
+	case muteListPathEnvKey:
+		return cfg.MuteListPath
+	}
+
+	for name, pattern := range cfg.LinkPatterns {
+		if linkTypePatternEnvKey(name) == envKey {
+			return pattern
+		}
+	}
+
+	return ""
+}
+
+// defaultLabels returns the default labels of the config file and `$ALLURE_LABEL_<NAME>` variables
+func defaultLabels() map[string]string {
+	labels := make(map[string]string)
+
+	for name, value := range getConfigFile().Labels {
+		labels[name] = value
+	}
+
+	for _, env := range os.Environ() {
+		key, value := splitEnv(env)
+		if value == "" || !strings.HasPrefix(key, labelEnvPrefix) {
+			continue
+		}
+
+		name := strings.ToLower(strings.TrimPrefix(key, labelEnvPrefix))
+		for fileName := range labels {
+			// the variable overrides the label of the file with the same name in any case
+			if strings.EqualFold(fileName, name) {
+				delete(labels, fileName)
+			}
+		}
+
+		labels[name] = value
+	}
+
+	return labels
+}
+
+// WithDefaultLabels Adds the default labels of the config file and `$ALLURE_LABEL_<NAME>` variables to the report
+// (e.g. `ALLURE_LABEL_OWNER=team` adds the label `owner`).
+// Returns a pointer to the current `allure.Result` (for Fluent Interface).
+func (result *Result) WithDefaultLabels() *Result {
+	labels := defaultLabels()
+	if len(labels) == 0 {
+		return result
+	}
+
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result.m.Lock()
+	defer result.m.Unlock()
+
+	for _, name := range names {
+		result.Labels = append(result.Labels, NewLabel(LabelType(name), labels[name]))
+	}
+
+	return result
+}
+
+// intValue returns the number setting as the environment variable value, empty if it is not set
+func intValue(value int) string {
+	if value == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(value)
+}
+
+func splitEnv(env string) (key, value string) {
+	if i := strings.Index(env, "="); i >= 0 {
+		return env[:i], env[i+1:]
+	}
+
+	return env, ""
+}
+
+func splitList(list string) []string {
+	var values []string
+
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
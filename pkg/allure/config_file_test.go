@@ -0,0 +1,144 @@
+package allure
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testConfigYAML = `
+outputPath: /tmp/results
+outputFolder: file-results
+issuePattern: https://issues/%s
+linkPatterns:
+  jira: https://jira/browse/{key}
+launchTags: [smoke, nightly]
+labels:
+  owner: team
+  layer: api
+redaction:
+  values: [file-secret]
+retries: 2
+timeout: 30s
+order: shuffle
+seed: 42
+filter: TestLogin
+labelFilter: tag=smoke && !tag=slow
+testPlanPath: testplan.json
+testPlan: "tests: [{id: 1042}]"
+`
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	yamlPath := filepath.Join(dir, "allure-go.yaml")
+	require.NoError(t, os.WriteFile(yamlPath, []byte(testConfigYAML), fileSystemPermissionCode))
+
+	cfg, err := LoadConfig(yamlPath)
+	require.NoError(t, err)
+	require.Equal(t, yamlPath, cfg.Path)
+	require.Equal(t, "/tmp/results", cfg.OutputPath)
+	require.Equal(t, map[string]string{"jira": "https://jira/browse/{key}"}, cfg.LinkPatterns)
+	require.Equal(t, []string{"smoke", "nightly"}, cfg.LaunchTags)
+	require.Equal(t, []string{"file-secret"}, cfg.Redaction.Values)
+	require.Equal(t, 2, cfg.Retries)
+	require.Equal(t, "30s", cfg.Timeout)
+	require.Equal(t, "shuffle", cfg.Order)
+	require.Equal(t, int64(42), cfg.Seed)
+	require.Equal(t, "tag=smoke && !tag=slow", cfg.LabelFilter)
+
+	jsonPath := filepath.Join(dir, "allure-go.json")
+	require.NoError(t, os.WriteFile(jsonPath, []byte(`{"outputFolder":"json-results","labels":{"owner":"team"}}`), fileSystemPermissionCode))
+
+	cfg, err = LoadConfig(jsonPath)
+	require.NoError(t, err)
+	require.Equal(t, "json-results", cfg.OutputFolder)
+	require.Equal(t, map[string]string{"owner": "team"}, cfg.Labels)
+
+	require.NoError(t, os.WriteFile(jsonPath, []byte(`{"retries":"many"}`), fileSystemPermissionCode))
+	_, err = LoadConfig(jsonPath)
+	require.Error(t, err)
+
+	_, err = LoadConfig(filepath.Join(dir, "missing.yaml"))
+	require.Error(t, err)
+}
+
+func TestFindConfigFile(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "internal", "tests")
+	require.NoError(t, os.MkdirAll(nested, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example\n"), fileSystemPermissionCode))
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(nested))
+	defer func() { require.NoError(t, os.Chdir(wd)) }()
+
+	require.Equal(t, "", FindConfigFile())
+
+	path := filepath.Join(root, "allure-go.yml")
+	require.NoError(t, os.WriteFile(path, []byte("retries: 1\n"), fileSystemPermissionCode))
+	found, err := filepath.EvalSymlinks(FindConfigFile())
+	require.NoError(t, err)
+	expected, err := filepath.EvalSymlinks(path)
+	require.NoError(t, err)
+	require.Equal(t, expected, found)
+
+	t.Setenv(configPathEnvKey, "custom.json")
+	require.Equal(t, "custom.json", FindConfigFile())
+}
+
+func TestGetConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "allure-go.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testConfigYAML), fileSystemPermissionCode))
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+
+	// registered before t.Setenv, so it runs after the environment is restored
+	t.Cleanup(func() { SetConfigFile(nil) })
+	SetConfigFile(cfg)
+
+	t.Setenv(outputFolderEnvKey, "env-results")
+	t.Setenv(retriesEnvKey, "3")
+	t.Setenv("ALLURE_LABEL_OWNER", "env-team")
+	t.Setenv("ALLURE_LINK_MR_PATTERN", "https://gitlab/mr/%s")
+
+	effective := GetConfig()
+	require.Equal(t, path, effective.Path)
+	require.Equal(t, "/tmp/results", effective.OutputPath)
+	require.Equal(t, "env-results", effective.OutputFolder)
+	require.Equal(t, 3, effective.Retries)
+	require.Equal(t, "TestLogin", effective.Filter)
+	require.Equal(t, "testplan.json", effective.TestPlanPath)
+	require.Equal(t, "tests: [{id: 1042}]", effective.TestPlan)
+	require.Equal(t, []string{"smoke", "nightly"}, effective.LaunchTags)
+	require.Equal(t, map[string]string{"owner": "env-team", "layer": "api"}, effective.Labels)
+	require.Equal(t, "https://jira/browse/{key}", effective.LinkPatterns["jira"])
+	require.Equal(t, "https://gitlab/mr/%s", effective.LinkPatterns["mr"])
+
+	require.Equal(t, filepath.Join("/tmp/results", "env-results"), getResultPath())
+	require.Equal(t, "https://issues/%s", getIssuePattern())
+	require.Equal(t, "https://jira/browse/ABC-1", LinkTo("jira", "ABC-1").URL)
+}
+
+func TestResult_WithDefaultLabels(t *testing.T) {
+	t.Cleanup(func() { SetConfigFile(nil) })
+	SetConfigFile(&Config{Labels: map[string]string{"owner": "team", "layer": "api"}, LaunchTags: []string{"smoke"}})
+
+	result := NewResult(testName, testFullName).WithLaunchTags().WithDefaultLabels()
+
+	tag, ok := result.GetFirstLabel(Tag)
+	require.True(t, ok)
+	require.Equal(t, "smoke", tag.GetValue())
+
+	owner, ok := result.GetFirstLabel(Owner)
+	require.True(t, ok)
+	require.Equal(t, "team", owner.GetValue())
+
+	layer, ok := result.GetFirstLabel(Layer)
+	require.True(t, ok)
+	require.Equal(t, "api", layer.GetValue())
+}
@@ -13,5 +13,25 @@ func TestConfig(t *testing.T) {
 	require.Equal(t, "ALLURE_TESTCASE_PATTERN", testCasePatternEnvKey)
 	require.Equal(t, "ALLURE_LAUNCH_TAGS", defaultTagsEnvKey)
 	require.Equal(t, "ALLURE_LINK_TMS_PATTERN", tmsLinkPatternEnvKey)
+	require.Equal(t, "ALLURE_VALIDATION", validationEnvKey)
+	require.Equal(t, "ALLURE_REDACT_ENV", redactEnvEnvKey)
+	require.Equal(t, "ALLURE_ID_STRATEGY", idStrategyEnvKey)
+	require.Equal(t, "ALLURE_CONFIG_PATH", configPathEnvKey)
+	require.Equal(t, "ALLURE_RETRIES", retriesEnvKey)
+	require.Equal(t, "ALLURE_MARK_FLAKY", markFlakyEnvKey)
+	require.Equal(t, "ALLURE_FILTER", filterEnvKey)
+	require.Equal(t, "ALLURE_LABELS", labelFilterEnvKey)
+	require.Equal(t, "ALLURE_TESTPLAN_PATH", testPlanPathEnvKey)
+	require.Equal(t, "ALLURE_TESTPLAN", testPlanEnvKey)
+	require.Equal(t, "ALLURE_MUTE_LIST_PATH", muteListPathEnvKey)
+	require.Equal(t, "ALLURE_TIMEOUT", timeoutEnvKey)
+	require.Equal(t, "ALLURE_ORDER", orderEnvKey)
+	require.Equal(t, "ALLURE_SEED", seedEnvKey)
+	require.Equal(t, "ALLURE_CLEANUP", cleanupEnvKey)
+	require.Equal(t, "ALLURE_KEEP_LAUNCHES", keepLaunchesEnvKey)
+	require.Equal(t, "ALLURE_LAUNCH_ID", launchIDEnvKey)
+	require.Equal(t, "ALLURE_LINK_", linkEnvPrefix)
+	require.Equal(t, "_PATTERN", patternEnvSuffix)
+	require.Equal(t, "ALLURE_LABEL_", labelEnvPrefix)
 	require.Equal(t, 0o644, fileSystemPermissionCode)
 }
@@ -17,6 +17,8 @@ type Container struct {
 	Afters   []*Step     `json:"afters,omitempty"`   // Array of pointers to allure.Step in Test TearDown
 	Start    int64       `json:"start,omitempty"`    // Start time of the container
 	Stop     int64       `json:"stop,omitempty"`     // Stop time of the container
+
+	sink Sink
 }
 
 // NewContainer - Constructor. Builds and returns a new `allure.Container` object.
@@ -31,6 +33,19 @@ func (container *Container) AddChild(child uuid.UUID) {
 	container.Children = append(container.Children, child)
 }
 
+// WithSink sets the Sink the container and its attachments will be printed to.
+// If sink is nil, the process-wide Sink is used (see SetSink).
+// Returns a pointer to the current `allure.Container` (for Fluent Interface).
+func (container *Container) WithSink(sink Sink) *Container {
+	container.sink = sink
+	return container
+}
+
+// GetSink returns the Sink the container and its attachments are printed to, the process-wide Sink if the container has no own one
+func (container *Container) GetSink() Sink {
+	return sinkOrDefault(container.sink)
+}
+
 // IsEmpty Returns `true` if arrays Container.Befores and Container.Afters are empty.
 func (container *Container) IsEmpty() bool {
 	return len(container.Befores) == 0 && len(container.Afters) == 0
@@ -41,31 +56,55 @@ func (container *Container) IsEmpty() bool {
 //  1. If the container is empty, execution of the function completes without error.
 //
 //  2. If the container contains steps
-//     2.1. Call Container.PrintAttachments()
-//     2.2. Serializes the file into `uuid4-container.json`.
-//     2.3. Creates a file in the file system in the output folder (`$ALLURE_OUTPUT_PATH`/`$ALLURE_OUTPUT_FOLDER`). If there is an error during
+//     2.1. Prints all attachments of the steps
+//     2.2. Redacts the registered secrets from the steps (see RedactValues).
+//     2.3. Validates the container according to the process-wide ValidationMode (see SetValidationMode).
+//     2.4. Serializes the file into `uuid4-container.json`.
+//     2.5. Writes the file to the container's Sink (by default - the output folder `$ALLURE_OUTPUT_PATH`/`$ALLURE_OUTPUT_FOLDER`).
 //
 // If error occurs during execution - returns it
 func (container *Container) Print() error {
-	if !container.IsEmpty() {
-		container.PrintAttachments()
+	if container.IsEmpty() {
+		return nil
+	}
+
+	errs := container.printAttachments()
+	container.redact()
 
-		return container.printContainer()
+	if GetValidationMode() != ValidationOff {
+		if err := applyValidation(container.validate(sinkOrDefault(container.sink))); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
-	return nil
+	if err := container.printContainer(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return joinErrors(errs)
 }
 
 // PrintAttachments It goes through all Container.Befores and Container.Afters
 // of the Container and calls the Container.PrintAttachments() method at each allure.Step.
 func (container *Container) PrintAttachments() {
+	_ = container.printAttachments()
+}
+
+func (container *Container) printAttachments() []error {
+	var (
+		sink = sinkOrDefault(container.sink)
+		errs []error
+	)
+
 	for _, step := range container.Befores {
-		step.PrintAttachments()
+		errs = append(errs, step.printAttachments(sink)...)
 	}
 
 	for _, step := range container.Afters {
-		step.PrintAttachments()
+		errs = append(errs, step.printAttachments(sink)...)
 	}
+
+	return errs
 }
 
 // Begin Sets `Container.Start` = allure.GetNow()
@@ -93,14 +132,14 @@ func (container *Container) ToJSON() ([]byte, error) {
 }
 
 // Print prints all attachments of [Container.Befores] and [Container.Afters]
-// after that marshals [Container] and [os.WriteFile]
+// after that marshals [Container] and writes it to the container's Sink
 func (container *Container) printContainer() error {
 	bResult, err := json.Marshal(container)
 	if err != nil {
 		return errors.Wrap(err, "Failed marshal Result")
 	}
 
-	err = NewFileManager().CreateFile(container.UUID.String()+"-container.json", bResult)
+	err = sinkOrDefault(container.sink).CreateFile(container.UUID.String()+containerFileSuffix, bResult)
 	if err != nil {
 		return errors.Wrap(err, "Error write Result")
 	}
@@ -0,0 +1,272 @@
+package allure
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Environment is the implementation of the `environment.properties` file.
+// Its entries are shown in the Environment widget of the Allure report.
+type Environment struct {
+	Entries map[string]string
+
+	sink Sink
+}
+
+// NewEnvironment - Constructor. Builds and returns a new empty `allure.Environment` object.
+func NewEnvironment() *Environment {
+	return &Environment{Entries: make(map[string]string)}
+}
+
+// ParseEnvironment parses the content of the `environment.properties` file
+func ParseEnvironment(content []byte) (*Environment, error) {
+	entries, err := parseProperties(content)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Environment{Entries: entries}, nil
+}
+
+// With adds the entry to the Environment. The entry with the same key is replaced.
+// Returns a pointer to the current `allure.Environment` (for Fluent Interface).
+func (env *Environment) With(key, value string) *Environment {
+	if env.Entries == nil {
+		env.Entries = make(map[string]string)
+	}
+
+	env.Entries[key] = value
+
+	return env
+}
+
+// WithSink sets the Sink the environment will be printed to.
+// If sink is nil, the process-wide Sink is used (see SetSink).
+// Returns a pointer to the current `allure.Environment` (for Fluent Interface).
+func (env *Environment) WithSink(sink Sink) *Environment {
+	env.sink = sink
+	return env
+}
+
+// Print merges the entries into `environment.properties` of the environment's Sink.
+// The entries already written (e.g. by other test packages of the launch) are kept, the ones with the same key are replaced.
+// The registered secrets (see RedactValues) are redacted from the values.
+func (env *Environment) Print() error {
+	return mergeFile(sinkOrDefault(env.sink), environmentFileName, func(existing []byte) ([]byte, error) {
+		entries, err := parseProperties(existing)
+		if err != nil {
+			return nil, err
+		}
+
+		redacted := false
+
+		for key, value := range env.Entries {
+			value, ok := redaction.redact(value)
+			entries[key] = value
+			redacted = redacted || ok
+		}
+
+		if redacted {
+			redaction.record()
+		}
+
+		return formatProperties(entries), nil
+	})
+}
+
+// formatProperties returns the entries in the `.properties` format sorted by the keys.
+// Non-ASCII characters are escaped, since the format is read in ISO-8859-1.
+func formatProperties(entries map[string]string) []byte {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	for _, key := range keys {
+		buf.WriteString(escapeProperty(key, true))
+		buf.WriteByte('=')
+		buf.WriteString(escapeProperty(entries[key], false))
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes()
+}
+
+func escapeProperty(s string, isKey bool) string {
+	var b strings.Builder
+
+	for i, r := range s {
+		switch r {
+		case '\\', '=', ':', '#', '!':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\f':
+			b.WriteString(`\f`)
+		case ' ':
+			if isKey || i == 0 {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(' ')
+		default:
+			if r < 0x20 || r > 0x7e {
+				for _, u := range utf16.Encode([]rune{r}) {
+					fmt.Fprintf(&b, `\u%04X`, u)
+				}
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// parseProperties parses the `.properties` content: `key=value`, `key: value` or `key value` lines,
+// comments starting with `#` or `!`, escapes and the lines continued with the trailing `\`
+func parseProperties(content []byte) (map[string]string, error) {
+	entries := make(map[string]string)
+
+	var logical string
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimLeft(scanner.Text(), " \t\f")
+		if logical == "" && (line == "" || line[0] == '#' || line[0] == '!') {
+			continue
+		}
+
+		if trailing := len(line) - len(strings.TrimRight(line, `\`)); trailing%2 == 1 {
+			logical += line[:len(line)-1]
+			continue
+		}
+
+		logical += line
+
+		key, value, err := splitProperty(logical)
+		if err != nil {
+			return nil, err
+		}
+
+		entries[key] = value
+		logical = ""
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if logical != "" {
+		key, value, err := splitProperty(logical)
+		if err != nil {
+			return nil, err
+		}
+
+		entries[key] = value
+	}
+
+	return entries, nil
+}
+
+func splitProperty(line string) (string, string, error) {
+	end := len(line)
+
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+
+		if line[i] == '=' || line[i] == ':' || line[i] == ' ' || line[i] == '\t' || line[i] == '\f' {
+			end = i
+			break
+		}
+	}
+
+	key, err := unescapeProperty(line[:end])
+	if err != nil {
+		return "", "", err
+	}
+
+	rest := strings.TrimLeft(line[end:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+
+	value, err := unescapeProperty(rest)
+	if err != nil {
+		return "", "", err
+	}
+
+	return key, value, nil
+}
+
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+
+	var (
+		b     strings.Builder
+		units []uint16
+	)
+
+	flush := func() {
+		b.WriteString(string(utf16.Decode(units)))
+		units = units[:0]
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			flush()
+			b.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch s[i] {
+		case 'n':
+			flush()
+			b.WriteByte('\n')
+		case 'r':
+			flush()
+			b.WriteByte('\r')
+		case 't':
+			flush()
+			b.WriteByte('\t')
+		case 'f':
+			flush()
+			b.WriteByte('\f')
+		case 'u':
+			if i+5 > len(s) {
+				return "", fmt.Errorf("malformed \\uXXXX escape in %q", s)
+			}
+
+			u, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("malformed \\uXXXX escape in %q", s)
+			}
+
+			units = append(units, uint16(u))
+			i += 4
+		default:
+			flush()
+			b.WriteByte(s[i])
+		}
+	}
+	flush()
+
+	return b.String(), nil
+}
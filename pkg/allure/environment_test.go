@@ -0,0 +1,90 @@
+package allure
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvironment_Print(t *testing.T) {
+	sink := NewMemorySink()
+
+	require.NoError(t, NewEnvironment().With("os", "linux").With("go", "1.17").WithSink(sink).Print())
+	require.NoError(t, NewEnvironment().With("go", "1.18").With("db", "postgres").WithSink(sink).Print())
+
+	content, ok := sink.File(environmentFileName)
+	require.True(t, ok)
+	require.Equal(t, "db=postgres\ngo=1.18\nos=linux\n", string(content))
+}
+
+func TestEnvironment_PrintConcurrently(t *testing.T) {
+	dir := t.TempDir()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			// every package of the launch has its own sink writing to the same directory
+			env := NewEnvironment().With(fmt.Sprintf("key%02d", i), "value").WithSink(NewFileSink(dir))
+			require.NoError(t, env.Print())
+		}(i)
+	}
+	wg.Wait()
+
+	content, err := os.ReadFile(filepath.Join(dir, environmentFileName))
+	require.NoError(t, err)
+
+	entries, err := parseProperties(content)
+	require.NoError(t, err)
+	require.Len(t, entries, 20)
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+}
+
+func TestProperties(t *testing.T) {
+	entries := map[string]string{
+		"simple":        "value",
+		"key with=sep:": " leading space and trailing\\",
+		"multiline":     "first\nsecond\tthird",
+		"unicode":       "привет 🙂",
+		"#comment":      "!value",
+		"empty":         "",
+	}
+
+	content := formatProperties(entries)
+	require.Contains(t, string(content), `unicode=\u043F\u0440\u0438\u0432\u0435\u0442 \uD83D\uDE42`)
+	require.Contains(t, string(content), `key\ with\=sep\:=\ leading space and trailing\\`)
+
+	parsed, err := parseProperties(content)
+	require.NoError(t, err)
+	require.Equal(t, entries, parsed)
+}
+
+func TestParseProperties(t *testing.T) {
+	content := "# comment\n! comment\n\n  first = one\nsecond:two\nthird three\nfourth=multi \\\n    line\nfifth\n"
+
+	entries, err := parseProperties([]byte(content))
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"first":  "one",
+		"second": "two",
+		"third":  "three",
+		"fourth": "multi line",
+		"fifth":  "",
+	}, entries)
+
+	_, err = parseProperties([]byte(`key=\u12`))
+	require.Error(t, err)
+
+	env, err := ParseEnvironment([]byte(content))
+	require.NoError(t, err)
+	require.Equal(t, entries, env.Entries)
+}
@@ -25,3 +25,16 @@ func (e *joinError) Error() string {
 func (e *joinError) Unwrap() []error {
 	return e.errs
 }
+
+// joinErrors returns nil if there are no errors, the only error if there is one
+// and joinError of all passed errors otherwise
+func joinErrors(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return &joinError{errs: errs}
+	}
+}
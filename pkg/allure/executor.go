@@ -0,0 +1,97 @@
+package allure
+
+import (
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+)
+
+// Executor is the implementation of the `executor.json` file.
+// It describes the CI (or any other system) that has run the tests and is shown in the Executors widget of the Allure report.
+type Executor struct {
+	Name       string `json:"name,omitempty"`       // Name of the executor (e.g. `Gitlab CI`)
+	Type       string `json:"type,omitempty"`       // Type of the executor, defines its icon (e.g. `gitlab`, `github`, `jenkins`, `teamcity`)
+	URL        string `json:"url,omitempty"`        // Link to the executor
+	BuildOrder int64  `json:"buildOrder,omitempty"` // Number of the build, used to order the builds in the history trend
+	BuildName  string `json:"buildName,omitempty"`  // Name of the build
+	BuildURL   string `json:"buildUrl,omitempty"`   // Link to the build
+	ReportName string `json:"reportName,omitempty"` // Title of the report
+	ReportURL  string `json:"reportUrl,omitempty"`  // Link to the report
+
+	sink Sink
+}
+
+// NewExecutor - Constructor. Builds and returns a new `allure.Executor` object with passed name and type.
+func NewExecutor(name, executorType string) *Executor {
+	return &Executor{Name: name, Type: executorType}
+}
+
+// WithURL sets the link to the executor.
+// Returns a pointer to the current `allure.Executor` (for Fluent Interface).
+func (executor *Executor) WithURL(url string) *Executor {
+	executor.URL = url
+	return executor
+}
+
+// WithBuild sets the number, the name and the link of the build.
+// Returns a pointer to the current `allure.Executor` (for Fluent Interface).
+func (executor *Executor) WithBuild(order int64, name, url string) *Executor {
+	executor.BuildOrder = order
+	executor.BuildName = name
+	executor.BuildURL = url
+
+	return executor
+}
+
+// WithReport sets the title and the link of the report.
+// Returns a pointer to the current `allure.Executor` (for Fluent Interface).
+func (executor *Executor) WithReport(name, url string) *Executor {
+	executor.ReportName = name
+	executor.ReportURL = url
+
+	return executor
+}
+
+// WithSink sets the Sink the executor will be printed to.
+// If sink is nil, the process-wide Sink is used (see SetSink).
+// Returns a pointer to the current `allure.Executor` (for Fluent Interface).
+func (executor *Executor) WithSink(sink Sink) *Executor {
+	executor.sink = sink
+	return executor
+}
+
+// Print merges the executor into `executor.json` of the executor's Sink.
+// The fields already written (e.g. by other test packages of the launch) are kept unless the executor sets them.
+func (executor *Executor) Print() error {
+	return mergeFile(sinkOrDefault(executor.sink), executorFileName, func(existing []byte) ([]byte, error) {
+		merged := new(Executor)
+		if len(existing) > 0 {
+			if err := json.Unmarshal(existing, merged); err != nil {
+				return nil, errors.Wrapf(err, "Failed unmarshal %s", executorFileName)
+			}
+		}
+
+		merged.merge(executor)
+
+		return json.MarshalIndent(merged, "", "  ")
+	})
+}
+
+func (executor *Executor) merge(other *Executor) {
+	mergeString(&executor.Name, other.Name)
+	mergeString(&executor.Type, other.Type)
+	mergeString(&executor.URL, other.URL)
+	mergeString(&executor.BuildName, other.BuildName)
+	mergeString(&executor.BuildURL, other.BuildURL)
+	mergeString(&executor.ReportName, other.ReportName)
+	mergeString(&executor.ReportURL, other.ReportURL)
+
+	if other.BuildOrder != 0 {
+		executor.BuildOrder = other.BuildOrder
+	}
+}
+
+func mergeString(dst *string, src string) {
+	if src != "" {
+		*dst = src
+	}
+}
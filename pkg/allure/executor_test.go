@@ -0,0 +1,33 @@
+package allure
+
+import (
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutor_Print(t *testing.T) {
+	sink := NewMemorySink()
+
+	executor := NewExecutor("Gitlab CI", "gitlab").
+		WithURL("https://gitlab.example.com").
+		WithBuild(42, "Pipeline #42", "https://gitlab.example.com/pipelines/42").
+		WithSink(sink)
+	require.NoError(t, executor.Print())
+	require.NoError(t, NewExecutor("", "").WithReport("Nightly", "https://reports.example.com/42").WithSink(sink).Print())
+
+	content, ok := sink.File(executorFileName)
+	require.True(t, ok)
+
+	printed := new(Executor)
+	require.NoError(t, json.Unmarshal(content, printed))
+	require.Equal(t, "Gitlab CI", printed.Name)
+	require.Equal(t, "gitlab", printed.Type)
+	require.Equal(t, "https://gitlab.example.com", printed.URL)
+	require.Equal(t, int64(42), printed.BuildOrder)
+	require.Equal(t, "Pipeline #42", printed.BuildName)
+	require.Equal(t, "https://gitlab.example.com/pipelines/42", printed.BuildURL)
+	require.Equal(t, "Nightly", printed.ReportName)
+	require.Equal(t, "https://reports.example.com/42", printed.ReportURL)
+}
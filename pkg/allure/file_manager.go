@@ -25,22 +25,16 @@ func NewFileManager() FileManager {
 func (m *fileManager) CreateFile(name string, content []byte) error {
 	file := filepath.Join(m.resultsPath, name)
 
-	return os.WriteFile(file, content, fileSystemPermissionCode)
+	return writeFileAtomic(file, content)
 }
 
+// createOutputDir creates the results directory and applies the CleanupPolicy to it
 func (m *fileManager) createOutputDir() {
-	isExists, err := exists(m.resultsPath)
-	if err != nil {
-		panic(err)
-	}
-
-	if !isExists {
-		_ = os.MkdirAll(m.resultsPath, os.ModePerm)
-	}
+	_ = prepareResultsDir(m.resultsPath)
 }
 
 func getOutputFolderName() string {
-	outputFolderName := os.Getenv(outputFolderEnvKey)
+	outputFolderName := configValue(outputFolderEnvKey)
 	if outputFolderName != "" {
 		return outputFolderName
 	}
@@ -49,7 +43,7 @@ func getOutputFolderName() string {
 }
 
 func getResultPath() string {
-	resultsPathToOutput := os.Getenv(resultsPathEnvKey)
+	resultsPathToOutput := configValue(resultsPathEnvKey)
 	outputFolderName := getOutputFolderName()
 
 	if resultsPathToOutput != "" {
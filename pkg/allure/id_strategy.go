@@ -0,0 +1,134 @@
+package allure
+
+import (
+	"strings"
+	"sync"
+)
+
+// IDStrategy computes the TestCaseID and the HistoryID of the result.
+// HistoryID is called after TestCaseID, so it may use the new `Result.TestCaseID`.
+// The strategy must not change the result.
+type IDStrategy interface {
+	TestCaseID(result *Result) string
+	HistoryID(result *Result) string
+}
+
+// FullNameIDStrategy is the default IDStrategy. It derives the IDs from `Result.FullName` only:
+// TestCaseID is the md5 hash of the full name, HistoryID is the md5 hash of the TestCaseID.
+// The results of the same test with different parameters share the history.
+type FullNameIDStrategy struct{}
+
+// TestCaseID returns the md5 hash of the full name
+func (FullNameIDStrategy) TestCaseID(result *Result) string {
+	return getMD5Hash(result.FullName)
+}
+
+// HistoryID returns the md5 hash of the md5 hash of the full name
+func (FullNameIDStrategy) HistoryID(result *Result) string {
+	return getMD5Hash(getMD5Hash(result.FullName))
+}
+
+// ParametersIDStrategy is the opt-in IDStrategy (`$ALLURE_ID_STRATEGY=parameters`). TestCaseID is the md5 hash of `Result.FullName`,
+// HistoryID is the md5 hash of the TestCaseID and the not excluded parameters (see Parameter.Excluded),
+// so the results of the data-driven cases have separate histories.
+// Without parameters, the IDs are the same as the ones of FullNameIDStrategy.
+type ParametersIDStrategy struct{}
+
+// TestCaseID returns the md5 hash of the full name
+func (ParametersIDStrategy) TestCaseID(result *Result) string {
+	return getMD5Hash(result.FullName)
+}
+
+// HistoryID returns the md5 hash of the TestCaseID and the parameters
+func (ParametersIDStrategy) HistoryID(result *Result) string {
+	return historyIDWithParameters(result)
+}
+
+// AllureIDStrategy uses the value of the `ALLURE_ID` label as the TestCaseID,
+// and the md5 hash of it and the not excluded parameters as the HistoryID,
+// so the history of the test survives its renaming.
+// The IDs of the results without `ALLURE_ID` label are computed by the Fallback strategy (FullNameIDStrategy if it is nil).
+type AllureIDStrategy struct {
+	Fallback IDStrategy
+}
+
+// TestCaseID returns the value of the `ALLURE_ID` label
+func (s AllureIDStrategy) TestCaseID(result *Result) string {
+	if id, ok := allureID(result); ok {
+		return id
+	}
+
+	return s.fallback().TestCaseID(result)
+}
+
+// HistoryID returns the md5 hash of the TestCaseID and the parameters
+func (s AllureIDStrategy) HistoryID(result *Result) string {
+	if _, ok := allureID(result); ok {
+		return historyIDWithParameters(result)
+	}
+
+	return s.fallback().HistoryID(result)
+}
+
+func (s AllureIDStrategy) fallback() IDStrategy {
+	if s.Fallback != nil {
+		return s.Fallback
+	}
+
+	return FullNameIDStrategy{}
+}
+
+func allureID(result *Result) (string, bool) {
+	label, ok := result.GetFirstLabel(AllureID)
+	if !ok || label.GetValue() == "" {
+		return "", false
+	}
+
+	return label.GetValue(), true
+}
+
+// historyIDWithParameters returns the md5 hash of the TestCaseID and the parameters taking part in the history
+func historyIDWithParameters(result *Result) string {
+	result.m.RLock()
+	defer result.m.RUnlock()
+
+	return getMD5Hash(result.TestCaseID + parametersHash(result.Parameters))
+}
+
+var (
+	idStrategyMu sync.RWMutex
+	idStrategy   = idStrategyFromEnv()
+)
+
+// SetIDStrategy sets the process-wide IDStrategy used by the results without their own strategy.
+// By default, it is taken from `$ALLURE_ID_STRATEGY` (`fullname`, `parameters` or `allure_id`), `fullname` if it is not set.
+// Passing nil restores the default.
+func SetIDStrategy(strategy IDStrategy) {
+	idStrategyMu.Lock()
+	defer idStrategyMu.Unlock()
+
+	if strategy == nil {
+		strategy = idStrategyFromEnv()
+	}
+
+	idStrategy = strategy
+}
+
+// GetIDStrategy returns the process-wide IDStrategy
+func GetIDStrategy() IDStrategy {
+	idStrategyMu.RLock()
+	defer idStrategyMu.RUnlock()
+
+	return idStrategy
+}
+
+func idStrategyFromEnv() IDStrategy {
+	switch strings.ToLower(strings.TrimSpace(configValue(idStrategyEnvKey))) {
+	case "parameters":
+		return ParametersIDStrategy{}
+	case "allure_id", "allureid":
+		return AllureIDStrategy{}
+	default:
+		return FullNameIDStrategy{}
+	}
+}
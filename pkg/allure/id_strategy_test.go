@@ -0,0 +1,82 @@
+package allure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFullNameIDStrategy(t *testing.T) {
+	result := NewResult(testName, testFullName).WithIDStrategy(FullNameIDStrategy{})
+	result.Parameters = NewParameters("case", "1")
+	result.UpdateIDs()
+
+	require.Equal(t, getMD5Hash(testFullName), result.TestCaseID)
+	require.Equal(t, getMD5Hash(getMD5Hash(testFullName)), result.HistoryID)
+}
+
+func TestParametersIDStrategy(t *testing.T) {
+	result := NewResult(testName, testFullName).WithIDStrategy(ParametersIDStrategy{})
+	require.Equal(t, getMD5Hash(testFullName), result.TestCaseID)
+	require.Equal(t, getMD5Hash(getMD5Hash(testFullName)), result.HistoryID)
+
+	result.Parameters = append(NewParameters("case", "1"), NewParameter("run", 1).Excluded())
+	result.UpdateIDs()
+	require.Equal(t, getMD5Hash(testFullName), result.TestCaseID)
+	require.Equal(t, getMD5Hash(getMD5Hash(testFullName)+parametersHash(NewParameters("case", "1"))), result.HistoryID)
+}
+
+func TestAllureIDStrategy(t *testing.T) {
+	result := NewResult(testName, testFullName).WithIDStrategy(AllureIDStrategy{Fallback: FullNameIDStrategy{}})
+	require.Equal(t, getMD5Hash(testFullName), result.TestCaseID)
+	require.Equal(t, getMD5Hash(getMD5Hash(testFullName)), result.HistoryID)
+
+	result.AddLabel(IDAllureLabel("123"))
+	result.Parameters = NewParameters("case", "1")
+	result.UpdateIDs()
+	require.Equal(t, "123", result.TestCaseID)
+	require.Equal(t, getMD5Hash("123"+parametersHash(result.Parameters)), result.HistoryID)
+
+	withoutLabel := NewResult(testName, testFullName).WithIDStrategy(AllureIDStrategy{})
+	withoutLabel.Parameters = NewParameters("case", "1")
+	withoutLabel.UpdateIDs()
+	require.Equal(t, FullNameIDStrategy{}.HistoryID(withoutLabel), withoutLabel.HistoryID)
+}
+
+func TestResult_UpdateIDs_keepsChanged(t *testing.T) {
+	result := NewResult(testName, testFullName).WithIDStrategy(ParametersIDStrategy{})
+	result.TestCaseID = "custom-test-case"
+	result.Parameters = NewParameters("case", "1")
+	result.UpdateIDs()
+
+	require.Equal(t, "custom-test-case", result.TestCaseID)
+	require.Equal(t, getMD5Hash("custom-test-case"+parametersHash(result.Parameters)), result.HistoryID)
+
+	result.HistoryID = "custom-history"
+	result.UpdateIDs()
+	require.Equal(t, "custom-history", result.HistoryID)
+}
+
+func TestSetIDStrategy(t *testing.T) {
+	// registered before t.Setenv, so it runs after the environment is restored
+	t.Cleanup(func() { SetIDStrategy(nil) })
+	require.Equal(t, FullNameIDStrategy{}, GetIDStrategy())
+
+	// the default keeps the HistoryID of the parameterized results
+	result := NewResult(testName, testFullName)
+	result.Parameters = NewParameters("case", "1")
+	require.NoError(t, result.WithSink(NewMemorySink()).Print())
+	require.Equal(t, getMD5Hash(getMD5Hash(testFullName)), result.HistoryID)
+
+	SetIDStrategy(AllureIDStrategy{})
+	require.Equal(t, AllureIDStrategy{}, GetIDStrategy())
+
+	result = NewResult(testName, testFullName)
+	result.AddLabel(IDAllureLabel("42"))
+	require.NoError(t, result.WithSink(NewMemorySink()).Print())
+	require.Equal(t, "42", result.TestCaseID)
+
+	t.Setenv(idStrategyEnvKey, "parameters")
+	SetIDStrategy(nil)
+	require.Equal(t, ParametersIDStrategy{}, GetIDStrategy())
+}
@@ -0,0 +1,289 @@
+// Package junit exports allure results to the JUnit XML format, understood by most CI systems and code-review tools.
+package junit
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ozontech/allure-go/pkg/allure"
+	"github.com/ozontech/allure-go/pkg/allure/results"
+)
+
+// suiteSeparator joins the parent suite and the suite into the name of the test suite
+const suiteSeparator = "/"
+
+// defaultSuiteName is the name of the test suite for the results without suite labels
+const defaultSuiteName = "default"
+
+// TestSuites is the root `<testsuites>` element of the JUnit XML report
+type TestSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []*TestSuite `xml:"testsuite"`
+}
+
+// TestSuite is the `<testsuite>` element, one per the suite of the results
+type TestSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Errors    int         `xml:"errors,attr"`
+	Skipped   int         `xml:"skipped,attr"`
+	Time      string      `xml:"time,attr"`
+	Timestamp string      `xml:"timestamp,attr,omitempty"`
+	TestCases []*TestCase `xml:"testcase"`
+
+	start, stop int64
+}
+
+// TestCase is the `<testcase>` element, one per result
+type TestCase struct {
+	Name      string   `xml:"name,attr"`
+	ClassName string   `xml:"classname,attr"`
+	Time      string   `xml:"time,attr"`
+	Failure   *Failure `xml:"failure,omitempty"`
+	Error     *Failure `xml:"error,omitempty"`
+	Skipped   *Skipped `xml:"skipped,omitempty"`
+	SystemOut string   `xml:"system-out,omitempty"`
+}
+
+// Failure is the `<failure>` or the `<error>` element of the test case
+type Failure struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Trace   string `xml:",chardata"`
+}
+
+// Skipped is the `<skipped>` element of the test case
+type Skipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// Convert converts the results to the JUnit test suites:
+//   - the test suite is named after the `parentSuite` and `suite` labels of the result;
+//   - `failed` status becomes `<failure>`, `broken` and `unknown` become `<error>` and `skipped` becomes `<skipped>`;
+//   - the steps are rendered into `<system-out>`.
+//
+// The suites and the test cases are sorted by name, so the same results always give the same report.
+func Convert(list []*allure.Result) *TestSuites {
+	return convert(list, nil)
+}
+
+// ConvertLaunch converts the results of the launch like Convert.
+// The setup and the teardown steps of the containers and the paths of the attachments are rendered into `<system-out>` too.
+func ConvertLaunch(launch *results.Launch) *TestSuites {
+	return convert(launch.Results, launch)
+}
+
+// Write writes the test suites as the JUnit XML document
+func Write(w io.Writer, suites *TestSuites) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(suites); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+func convert(list []*allure.Result, launch *results.Launch) *TestSuites {
+	var (
+		report = new(TestSuites)
+		suites = make(map[string]*TestSuite)
+	)
+
+	for _, result := range list {
+		name := suiteName(result)
+
+		suite, ok := suites[name]
+		if !ok {
+			suite = &TestSuite{Name: name}
+			suites[name] = suite
+			report.Suites = append(report.Suites, suite)
+		}
+
+		suite.add(newTestCase(result, name, launch), result)
+	}
+
+	sort.Slice(report.Suites, func(i, j int) bool {
+		return report.Suites[i].Name < report.Suites[j].Name
+	})
+
+	var total time.Duration
+
+	for _, suite := range report.Suites {
+		sort.SliceStable(suite.TestCases, func(i, j int) bool {
+			return suite.TestCases[i].Name < suite.TestCases[j].Name
+		})
+
+		duration := millis(suite.start, suite.stop)
+		suite.Time = seconds(duration)
+		total += duration
+
+		if suite.start > 0 {
+			suite.Timestamp = time.Unix(0, suite.start*int64(time.Millisecond)).UTC().Format("2006-01-02T15:04:05")
+		}
+
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Errors += suite.Errors
+		report.Skipped += suite.Skipped
+	}
+
+	report.Time = seconds(total)
+
+	return report
+}
+
+func (suite *TestSuite) add(testCase *TestCase, result *allure.Result) {
+	suite.TestCases = append(suite.TestCases, testCase)
+	suite.Tests++
+
+	switch {
+	case testCase.Failure != nil:
+		suite.Failures++
+	case testCase.Error != nil:
+		suite.Errors++
+	case testCase.Skipped != nil:
+		suite.Skipped++
+	}
+
+	if result.Start > 0 && (suite.start == 0 || result.Start < suite.start) {
+		suite.start = result.Start
+	}
+
+	if result.Stop > suite.stop {
+		suite.stop = result.Stop
+	}
+}
+
+func suiteName(result *allure.Result) string {
+	var parts []string
+
+	for _, labelType := range []allure.LabelType{allure.ParentSuite, allure.Suite} {
+		if label, ok := result.GetFirstLabel(labelType); ok && label.GetValue() != "" {
+			parts = append(parts, label.GetValue())
+		}
+	}
+
+	if len(parts) == 0 {
+		return defaultSuiteName
+	}
+
+	return strings.Join(parts, suiteSeparator)
+}
+
+func newTestCase(result *allure.Result, suite string, launch *results.Launch) *TestCase {
+	name := result.Name
+	if label, ok := result.GetFirstLabel(allure.SubSuite); ok && label.GetValue() != "" {
+		name = label.GetValue() + suiteSeparator + name
+	}
+
+	testCase := &TestCase{
+		Name:      name,
+		ClassName: suite,
+		Time:      seconds(millis(result.Start, result.Stop)),
+		SystemOut: systemOut(result, launch),
+	}
+
+	details := result.StatusDetails
+
+	switch result.Status {
+	case allure.Failed:
+		testCase.Failure = &Failure{Message: details.Message, Type: string(result.Status), Trace: details.Trace}
+	case allure.Broken, allure.Unknown:
+		testCase.Error = &Failure{Message: details.Message, Type: string(result.Status), Trace: details.Trace}
+	case allure.Skipped:
+		testCase.Skipped = &Skipped{Message: details.Message}
+	}
+
+	return testCase
+}
+
+// systemOut renders the steps of the result (and of its containers if the launch is passed) as an indented tree
+func systemOut(result *allure.Result, launch *results.Launch) string {
+	var b strings.Builder
+
+	if launch != nil {
+		for _, container := range launch.ContainersOf(result.UUID) {
+			writeSteps(&b, container.Befores, 0)
+		}
+	}
+
+	writeSteps(&b, result.Steps, 0)
+
+	if launch != nil {
+		for _, container := range launch.ContainersOf(result.UUID) {
+			writeSteps(&b, container.Afters, 0)
+		}
+
+		for _, attachment := range launch.Attachments(result) {
+			fmt.Fprintf(&b, "[[ATTACHMENT|%s]]\n", attachment.Path)
+		}
+	}
+
+	return b.String()
+}
+
+func writeSteps(b *strings.Builder, steps []*allure.Step, depth int) {
+	for _, step := range steps {
+		if step == nil {
+			continue
+		}
+
+		indent := strings.Repeat("  ", depth)
+
+		fmt.Fprintf(b, "%s[%s] %s (%s)\n", indent, stepStatus(step.Status), step.Name, millis(step.Start, step.Stop))
+
+		for _, param := range step.Parameters {
+			if param == nil || param.GetMode() == allure.ParameterModeHidden {
+				continue
+			}
+
+			fmt.Fprintf(b, "%s    %s = %s\n", indent, param.Name, param.GetValue())
+		}
+
+		if message := strings.TrimSpace(step.StatusDetails.Message); message != "" && step.Status != allure.Passed {
+			for _, line := range strings.Split(message, "\n") {
+				fmt.Fprintf(b, "%s    %s\n", indent, line)
+			}
+		}
+
+		writeSteps(b, step.Steps, depth+1)
+	}
+}
+
+func stepStatus(status allure.Status) string {
+	if status == "" {
+		return string(allure.Unknown)
+	}
+
+	return string(status)
+}
+
+func millis(start, stop int64) time.Duration {
+	if start <= 0 || stop < start {
+		return 0
+	}
+
+	return time.Duration(stop-start) * time.Millisecond
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
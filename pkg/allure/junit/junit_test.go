@@ -0,0 +1,106 @@
+package junit
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ozontech/allure-go/pkg/allure"
+	"github.com/ozontech/allure-go/pkg/allure/results"
+)
+
+func newResult(name string, status allure.Status, labels ...*allure.Label) *allure.Result {
+	result := allure.NewResult(name, "pkg/"+name)
+	result.AddLabel(labels...)
+	result.Status = status
+	result.Start = 1000
+	result.Stop = 2500
+
+	return result
+}
+
+func TestConvert(t *testing.T) {
+	passed := newResult("TestPassed", allure.Passed, allure.ParentSuiteLabel("Parent"), allure.SuiteLabel("Suite"))
+	step := allure.NewStep("step", allure.Failed, 1000, 1200, []*allure.Parameter{allure.NewParameter("key", "value"), allure.NewParameter("hidden", "value").Hidden()})
+	step.StatusDetails.Message = "whoops"
+	step.Steps = append(step.Steps, allure.NewStep("nested", allure.Passed, 1000, 1100, nil))
+	passed.Steps = append(passed.Steps, step)
+
+	failed := newResult("TestFailed", allure.Failed, allure.SuiteLabel("Suite"), allure.ParentSuiteLabel("Parent"), allure.SubSuiteLabel("Sub"))
+	failed.StatusDetails = allure.StatusDetail{Message: "expected 1", Trace: "trace"}
+
+	broken := newResult("TestBroken", allure.Broken, allure.SuiteLabel("Other"))
+	skipped := newResult("TestSkipped", allure.Skipped)
+	skipped.StatusDetails.Message = "not now"
+
+	report := Convert([]*allure.Result{passed, failed, broken, skipped})
+	require.Equal(t, 4, report.Tests)
+	require.Equal(t, 1, report.Failures)
+	require.Equal(t, 1, report.Errors)
+	require.Equal(t, 1, report.Skipped)
+	require.Equal(t, "4.500", report.Time)
+
+	require.Len(t, report.Suites, 3)
+	require.Equal(t, "Other", report.Suites[0].Name)
+	require.Equal(t, "Parent/Suite", report.Suites[1].Name)
+	require.Equal(t, "default", report.Suites[2].Name)
+
+	suite := report.Suites[1]
+	require.Equal(t, 2, suite.Tests)
+	require.Equal(t, "1.500", suite.Time)
+	require.Equal(t, "1970-01-01T00:00:01", suite.Timestamp)
+	require.Equal(t, "Sub/TestFailed", suite.TestCases[0].Name)
+	require.Equal(t, "Parent/Suite", suite.TestCases[0].ClassName)
+	require.Equal(t, &Failure{Message: "expected 1", Type: "failed", Trace: "trace"}, suite.TestCases[0].Failure)
+
+	require.Equal(t, "TestPassed", suite.TestCases[1].Name)
+	require.Nil(t, suite.TestCases[1].Failure)
+	require.Equal(t, "[failed] step (200ms)\n    key = value\n    whoops\n  [passed] nested (100ms)\n", suite.TestCases[1].SystemOut)
+
+	require.Equal(t, "broken", report.Suites[0].TestCases[0].Error.Type)
+	require.Equal(t, &Skipped{Message: "not now"}, report.Suites[2].TestCases[0].Skipped)
+}
+
+func TestConvertLaunch(t *testing.T) {
+	dir := t.TempDir()
+	sink := allure.NewFileSink(dir)
+	allure.SetSink(sink)
+	defer allure.SetSink(nil)
+
+	result := newResult("Test", allure.Passed).WithSink(sink)
+	attachment := allure.NewAttachment("log", allure.Text, []byte("log"))
+	result.Attachments = append(result.Attachments, attachment)
+	require.NoError(t, result.Print())
+
+	container := allure.NewContainer().WithSink(sink)
+	container.AddChild(result.UUID)
+	container.Befores = append(container.Befores, allure.NewStep("setup", allure.Passed, 1000, 1000, nil))
+	require.NoError(t, container.Print())
+
+	launch, err := results.Read(dir)
+	require.NoError(t, err)
+
+	report := ConvertLaunch(launch)
+	require.Len(t, report.Suites, 1)
+
+	path, _ := launch.Attachment(attachment.Source)
+	require.Equal(t, "[passed] setup (0s)\n[[ATTACHMENT|"+path.Path+"]]\n", report.Suites[0].TestCases[0].SystemOut)
+}
+
+func TestWrite(t *testing.T) {
+	failed := newResult("TestFailed", allure.Failed, allure.SuiteLabel("Suite"))
+	failed.StatusDetails = allure.StatusDetail{Message: "a < b", Trace: "<trace>"}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, Convert([]*allure.Result{failed})))
+	require.Contains(t, buf.String(), xml.Header)
+	require.Contains(t, buf.String(), `<failure message="a &lt; b" type="failed">&lt;trace&gt;</failure>`)
+
+	parsed := new(TestSuites)
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), parsed))
+	require.Equal(t, 1, parsed.Failures)
+	require.Equal(t, "Suite", parsed.Suites[0].Name)
+	require.Equal(t, "<trace>", parsed.Suites[0].TestCases[0].Failure.Trace)
+}
@@ -85,6 +85,8 @@ const (
 	Owner       LabelType = "owner"
 	Lead        LabelType = "lead"
 	AllureID    LabelType = "ALLURE_ID"
+	Redacted    LabelType = "redacted"
+	Seed        LabelType = "seed"
 )
 
 // ToString ...
@@ -220,3 +222,8 @@ func LeadLabel(leadName string) *Label {
 func IDAllureLabel(allureID string) *Label {
 	return NewLabel(AllureID, allureID)
 }
+
+// SeedLabel returns Seed Label: the seed the tests were shuffled with
+func SeedLabel(seed int64) *Label {
+	return NewLabel(Seed, strconv.FormatInt(seed, 10))
+}
@@ -26,6 +26,7 @@ func TestLabelType_ToString(t *testing.T) {
 	owner := "owner"
 	lead := "lead"
 	allure_id := "ALLURE_ID"
+	seed := "seed"
 
 	require.Equal(t, epic, Epic.ToString())
 	require.Equal(t, layer, Layer.ToString())
@@ -45,6 +46,7 @@ func TestLabelType_ToString(t *testing.T) {
 	require.Equal(t, owner, Owner.ToString())
 	require.Equal(t, lead, Lead.ToString())
 	require.Equal(t, allure_id, AllureID.ToString())
+	require.Equal(t, seed, Seed.ToString())
 }
 
 func TestSeverityType_ToString(t *testing.T) {
@@ -80,6 +82,7 @@ func TestLabelCreation(t *testing.T) {
 	owner := OwnerLabel("ownerTest")
 	lead := LeadLabel("leadTest")
 	idAllure := IDAllureLabel("idAllureTest")
+	seed := SeedLabel(42)
 	numLabel := NewLabel(ID, 24.2)
 	boolLabel := NewLabel(Epic, true)
 
@@ -101,6 +104,7 @@ func TestLabelCreation(t *testing.T) {
 	require.Equal(t, owner.Name, Owner.ToString())
 	require.Equal(t, lead.Name, Lead.ToString())
 	require.Equal(t, idAllure.Name, AllureID.ToString())
+	require.Equal(t, seed.Name, Seed.ToString())
 
 	require.Equal(t, "epicTest", epic.GetValue())
 	require.Equal(t, "featureTest", feature.GetValue())
@@ -119,6 +123,7 @@ func TestLabelCreation(t *testing.T) {
 	require.Equal(t, "ownerTest", owner.GetValue())
 	require.Equal(t, "leadTest", lead.GetValue())
 	require.Equal(t, "idAllureTest", idAllure.GetValue())
+	require.Equal(t, "42", seed.GetValue())
 
 	require.Equal(t, "24.2", numLabel.GetValue())
 	require.Equal(t, "true", boolLabel.GetValue())
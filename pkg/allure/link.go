@@ -2,7 +2,6 @@ package allure
 
 import (
 	"fmt"
-	"os"
 	"strings"
 )
 
@@ -81,7 +80,7 @@ func getTmsPattern() string {
 }
 
 func getPattern(envKey string, defaultPattern string) string {
-	pattern := os.Getenv(envKey)
+	pattern := configValue(envKey)
 
 	if !strings.Contains(pattern, "%s") {
 		fmt.Printf("Provided pattern (%s) is either missing '%%s' or empty.\n", pattern)
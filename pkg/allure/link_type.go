@@ -0,0 +1,160 @@
+package allure
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// LinkType is a named link type of the registry used by LinkTo (e.g. `jira`, `mr` or `runbook`)
+type LinkType struct {
+	Name    string    // Name of the type passed to LinkTo
+	Type    LinkTypes // Allure type of the links, LINK if empty
+	Pattern string    // URL template with the placeholders (e.g. `https://gitlab.com/{project}/-/merge_requests/{id}`) or `%s`
+}
+
+// linkPlaceholder matches the named placeholders of the link pattern
+var linkPlaceholder = regexp.MustCompile(`\{[A-Za-z0-9_.-]*\}`)
+
+// notEnvChar matches the characters of the link type name replaced in the environment variable name
+var notEnvChar = regexp.MustCompile(`[^A-Z0-9]+`)
+
+var (
+	linkTypesMu sync.RWMutex
+	linkTypes   = make(map[string]LinkType)
+
+	// unknownLinkTypes are the unknown link types already reported by LinkTo
+	unknownLinkTypes sync.Map
+)
+
+// RegisterLinkType adds the link type to the process-wide registry, replacing the type with the same name.
+// The types registered in code take precedence over the patterns of the environment variables.
+func RegisterLinkType(linkType LinkType) {
+	linkTypesMu.Lock()
+	defer linkTypesMu.Unlock()
+
+	linkTypes[strings.ToLower(linkType.Name)] = linkType
+}
+
+// GetLinkType returns the link type by its name and true if it is known.
+// If the type is not registered in code, its pattern is taken from `$ALLURE_LINK_<NAME>_PATTERN`
+// (e.g. `ALLURE_LINK_JIRA_PATTERN` for `jira`) or `linkPatterns` of the config file; the `issue` and `test_case` types
+// also use `$ALLURE_ISSUE_PATTERN` and `$ALLURE_TESTCASE_PATTERN`.
+func GetLinkType(name string) (LinkType, bool) {
+	linkTypesMu.RLock()
+	linkType, ok := linkTypes[strings.ToLower(name)]
+	linkTypesMu.RUnlock()
+
+	if ok {
+		return linkType, true
+	}
+
+	pattern := configValue(linkTypePatternEnvKey(name))
+
+	switch LinkTypes(strings.ToLower(name)) {
+	case ISSUE:
+		if pattern == "" {
+			pattern = configValue(issuePatternEnvKey)
+		}
+		linkType = LinkType{Name: name, Type: ISSUE, Pattern: pattern}
+	case TESTCASE:
+		if pattern == "" {
+			pattern = configValue(testCasePatternEnvKey)
+		}
+		linkType = LinkType{Name: name, Type: TESTCASE, Pattern: pattern}
+	case TMS:
+		linkType = LinkType{Name: name, Type: TMS, Pattern: pattern}
+	default:
+		linkType = LinkType{Name: name, Type: LINK, Pattern: pattern}
+	}
+
+	return linkType, linkType.Pattern != ""
+}
+
+// LinkTo returns the link of the named type (see RegisterLinkType and GetLinkType) with the URL built from its pattern.
+// The value `name=value` fills the placeholder `{name}`, the other values fill the rest of the placeholders (or `%s`)
+// in the order they appear, e.g. `LinkTo("grafana", "payments", "42")` or `LinkTo("grafana", "panel=42", "payments")`
+// for `https://grafana/d/{dashboard}?viewPanel={panel}`. The values are escaped with url.PathEscape, `/` is kept.
+// The link is named `<type>[<values>]`. If the type is unknown, the link has no URL and the type is reported once.
+func LinkTo(typeName string, values ...string) *Link {
+	name := fmt.Sprintf("%s[%s]", typeName, strings.Join(values, ", "))
+
+	linkType, ok := GetLinkType(typeName)
+	if !ok {
+		if _, reported := unknownLinkTypes.LoadOrStore(strings.ToLower(typeName), true); !reported {
+			fmt.Printf("Link type (%s) is unknown.\n", typeName)
+			fmt.Printf("Use %s environment variable or allure.RegisterLinkType to supply its pattern.\n", linkTypePatternEnvKey(typeName))
+		}
+
+		return NewLink(name, LINK, "")
+	}
+
+	if linkType.Type == "" {
+		linkType.Type = LINK
+	}
+
+	return NewLink(name, linkType.Type, expandLinkPattern(linkType.Pattern, values))
+}
+
+// linkTypePatternEnvKey returns the name of the environment variable with the pattern of the link type
+func linkTypePatternEnvKey(name string) string {
+	return linkEnvPrefix + strings.Trim(notEnvChar.ReplaceAllString(strings.ToUpper(name), "_"), "_") + patternEnvSuffix
+}
+
+// expandLinkPattern replaces the placeholders of the pattern with the escaped values.
+// The value `name=value` fills the placeholder `{name}` of the pattern, the other values fill the rest
+// of the placeholders in the order they appear. The placeholders with the same name get the same value.
+// The placeholders left without values are removed.
+func expandLinkPattern(pattern string, values []string) string {
+	if !linkPlaceholder.MatchString(pattern) {
+		for _, value := range values {
+			if !strings.Contains(pattern, "%s") {
+				break
+			}
+
+			pattern = strings.Replace(pattern, "%s", escapeLinkValue(value), 1)
+		}
+
+		return strings.ReplaceAll(pattern, "%s", "")
+	}
+
+	var (
+		named      = make(map[string]string)
+		positional = make([]string, 0, len(values))
+	)
+
+	for _, value := range values {
+		if i := strings.Index(value, "="); i > 0 && strings.Contains(pattern, "{"+value[:i]+"}") {
+			named["{"+value[:i]+"}"] = escapeLinkValue(value[i+1:])
+			continue
+		}
+
+		positional = append(positional, value)
+	}
+
+	return linkPlaceholder.ReplaceAllStringFunc(pattern, func(placeholder string) string {
+		if value, ok := named[placeholder]; ok {
+			return value
+		}
+
+		var value string
+		if len(positional) > 0 {
+			value, positional = escapeLinkValue(positional[0]), positional[1:]
+		}
+		named[placeholder] = value
+
+		return value
+	})
+}
+
+// escapeLinkValue escapes the value with url.PathEscape keeping `/`, so the value can be a path (e.g. a GitLab project)
+func escapeLinkValue(value string) string {
+	segments := strings.Split(value, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,62 @@
+package allure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinkTo_registered(t *testing.T) {
+	RegisterLinkType(LinkType{Name: "mr", Pattern: "https://gitlab.com/{project}/-/merge_requests/{id}"})
+	RegisterLinkType(LinkType{Name: "Jira", Type: ISSUE, Pattern: "https://jira.com/browse/%s"})
+
+	mr := LinkTo("mr", "pay/api", "42")
+	require.Equal(t, "mr[pay/api, 42]", mr.Name)
+	require.Equal(t, string(LINK), mr.Type)
+	require.Equal(t, "https://gitlab.com/pay/api/-/merge_requests/42", mr.URL)
+
+	jira := LinkTo("jira", "PAY-42")
+	require.Equal(t, "jira[PAY-42]", jira.Name)
+	require.Equal(t, string(ISSUE), jira.Type)
+	require.Equal(t, "https://jira.com/browse/PAY-42", jira.URL)
+}
+
+func TestLinkTo_env(t *testing.T) {
+	t.Setenv("ALLURE_LINK_GRAFANA_DASHBOARD_PATTERN", "https://grafana/d/{dashboard}?viewPanel={panel}&from={dashboard}")
+	t.Setenv(issuePatternEnvKey, "https://issues/%s")
+
+	grafana := LinkTo("grafana-dashboard", "payments", "7")
+	require.Equal(t, string(LINK), grafana.Type)
+	require.Equal(t, "https://grafana/d/payments?viewPanel=7&from=payments", grafana.URL)
+
+	issue := LinkTo("issue", "PAY-1")
+	require.Equal(t, string(ISSUE), issue.Type)
+	require.Equal(t, "https://issues/PAY-1", issue.URL)
+}
+
+func TestLinkTo_unknown(t *testing.T) {
+	link := LinkTo("unknown-type", "a", "b")
+	require.Equal(t, "unknown-type[a, b]", link.Name)
+	require.Equal(t, string(LINK), link.Type)
+	require.Empty(t, link.URL)
+
+	_, reported := unknownLinkTypes.Load("unknown-type")
+	require.True(t, reported)
+}
+
+func TestExpandLinkPattern(t *testing.T) {
+	require.Equal(t, "https://host/a/b", expandLinkPattern("https://host/%s/%s", []string{"a", "b"}))
+	require.Equal(t, "https://host/a/", expandLinkPattern("https://host/%s/%s", []string{"a"}))
+	require.Equal(t, "https://host/a", expandLinkPattern("https://host/{x}", []string{"a", "b"}))
+	require.Equal(t, "https://host/a/", expandLinkPattern("https://host/{x}/{y}", []string{"a"}))
+	require.Equal(t, "https://host/b/a", expandLinkPattern("https://host/{x}/{y}", []string{"a", "x=b"}))
+	require.Equal(t, "https://host/a/z=b", expandLinkPattern("https://host/{x}/{y}", []string{"a", "z=b"}))
+	require.Equal(t, "https://host/a%20b%3F/c%25d", expandLinkPattern("https://host/{x}", []string{"a b?/c%d"}))
+	require.Equal(t, "https://host/a%23b", expandLinkPattern("https://host/%s", []string{"a#b"}))
+}
+
+func TestLinkTypePatternEnvKey(t *testing.T) {
+	require.Equal(t, "ALLURE_LINK_JIRA_PATTERN", linkTypePatternEnvKey("jira"))
+	require.Equal(t, "ALLURE_LINK_GRAFANA_DASHBOARD_PATTERN", linkTypePatternEnvKey("grafana-dashboard"))
+	require.Equal(t, tmsLinkPatternEnvKey, linkTypePatternEnvKey("tms"))
+}
@@ -0,0 +1,145 @@
+package allure
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// staleLockAge is less than lockTimeout, so the waiters take over the lock of a crashed process before they time out.
+// The holder refreshes the modification time of its lock every lockRefreshInterval, so a live lock never gets stale.
+const (
+	lockRetryInterval   = 10 * time.Millisecond
+	lockTimeout         = 30 * time.Second
+	staleLockAge        = 10 * time.Second
+	lockRefreshInterval = staleLockAge / 4
+)
+
+// lockFile acquires the lock shared between goroutines and processes by creating the lock file exclusively.
+// The lock file holds the owner token (the PID and a unique ID of the holder), its modification time is refreshed
+// while the lock is held. A lock file older than staleLockAge is considered abandoned by a crashed process and is removed.
+// Returns the function that releases the lock, it removes the lock file only if it is still owned by the holder.
+func lockFile(path string) (func(), error) {
+	deadline := time.Now().Add(lockTimeout)
+	token := fmt.Sprintf("%d %s", os.Getpid(), uuid.New())
+
+	for {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, fileSystemPermissionCode)
+		if err == nil {
+			_, err = file.WriteString(token)
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				_ = os.Remove(path)
+				return nil, errors.Wrapf(err, "Cannot write lock %s", path)
+			}
+
+			return holdLock(path, token), nil
+		}
+
+		if !os.IsExist(err) {
+			return nil, errors.Wrapf(err, "Cannot create lock %s", path)
+		}
+
+		if removeStaleLock(path) {
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, errors.Errorf("Timeout of %s exceeded while waiting for lock %s", lockTimeout, path)
+		}
+
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// holdLock refreshes the modification time of the lock file owned by the token until the returned unlock is called.
+// unlock removes the lock file if it still holds the token: the lock taken over by another process is kept.
+func holdLock(path, token string) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(lockRefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if !ownsLock(path, token) {
+					return
+				}
+
+				now := time.Now()
+				_ = os.Chtimes(path, now, now)
+			}
+		}
+	}()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-stopped
+
+			if ownsLock(path, token) {
+				_ = os.Remove(path)
+			}
+		})
+	}
+}
+
+// ownsLock returns true if the lock file holds the owner token
+func ownsLock(path, token string) bool {
+	content, err := os.ReadFile(filepath.Clean(path))
+
+	return err == nil && string(content) == token
+}
+
+// removeStaleLock removes the lock file older than staleLockAge and returns true if it was removed.
+// The lock is removed while holding the exclusive break lock and its age is checked again under it,
+// so two waiters can't both remove it: the second one sees the fresh lock of the first one.
+func removeStaleLock(path string) bool {
+	if !isStaleLock(path) {
+		return false
+	}
+
+	breakPath := path + ".break"
+
+	file, err := os.OpenFile(breakPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, fileSystemPermissionCode)
+	if err != nil {
+		// the break lock is left by a waiter crashed while removing the stale lock
+		if os.IsExist(err) && isStaleLock(breakPath) {
+			_ = os.Remove(breakPath)
+		}
+
+		return false
+	}
+	_ = file.Close()
+
+	defer func() { _ = os.Remove(breakPath) }()
+
+	if !isStaleLock(path) {
+		return false
+	}
+
+	return os.Remove(path) == nil
+}
+
+// isStaleLock returns true if the lock file exists and is older than staleLockAge
+func isStaleLock(path string) bool {
+	info, err := os.Stat(path)
+
+	return err == nil && time.Since(info.ModTime()) > staleLockAge
+}
@@ -0,0 +1,126 @@
+package allure
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".test.lock")
+
+	unlock, err := lockFile(path)
+	require.NoError(t, err)
+
+	locked := make(chan struct{})
+	released := make(chan struct{})
+	go func() {
+		defer close(released)
+
+		unlockSecond, err := lockFile(path)
+		require.NoError(t, err)
+		close(locked)
+		unlockSecond()
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("lock is acquired twice")
+	case <-time.After(5 * lockRetryInterval):
+	}
+
+	unlock()
+	<-locked
+	<-released
+
+	_, err = os.Stat(path)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestLockFile_Stale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".test.lock")
+	require.NoError(t, os.WriteFile(path, []byte("1"), fileSystemPermissionCode))
+
+	abandoned := time.Now().Add(-2 * staleLockAge)
+	require.NoError(t, os.Chtimes(path, abandoned, abandoned))
+
+	unlock, err := lockFile(path)
+	require.NoError(t, err)
+	unlock()
+}
+
+func TestLockFile_StaleTakeover(t *testing.T) {
+	require.Less(t, int64(staleLockAge), int64(lockTimeout))
+
+	path := filepath.Join(t.TempDir(), ".test.lock")
+	require.NoError(t, os.WriteFile(path, []byte("1"), fileSystemPermissionCode))
+
+	abandoned := time.Now().Add(-2 * staleLockAge)
+	require.NoError(t, os.Chtimes(path, abandoned, abandoned))
+
+	var (
+		wg      sync.WaitGroup
+		holders int32
+		overlap int32
+	)
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			unlock, err := lockFile(path)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			if atomic.AddInt32(&holders, 1) > 1 {
+				atomic.StoreInt32(&overlap, 1)
+			}
+			time.Sleep(lockRetryInterval)
+			atomic.AddInt32(&holders, -1)
+
+			unlock()
+		}()
+	}
+	wg.Wait()
+
+	require.Zero(t, atomic.LoadInt32(&overlap), "the stale lock is taken over twice")
+
+	_, err := os.Stat(path + ".break")
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestLockFile_Refresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".test.lock")
+
+	unlock, err := lockFile(path)
+	require.NoError(t, err)
+	defer unlock()
+
+	// the lock held longer than staleLockAge is refreshed by its holder and is not taken over
+	old := time.Now().Add(-2 * staleLockAge)
+	require.NoError(t, os.Chtimes(path, old, old))
+	require.Eventually(t, func() bool { return !isStaleLock(path) }, 2*lockRefreshInterval, lockRetryInterval)
+}
+
+func TestLockFile_UnlockTakenOver(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".test.lock")
+
+	unlock, err := lockFile(path)
+	require.NoError(t, err)
+
+	// the lock is taken over by another process, unlock keeps its lock file
+	require.NoError(t, os.WriteFile(path, []byte("1 other"), fileSystemPermissionCode))
+	unlock()
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "1 other", string(content))
+}
@@ -0,0 +1,127 @@
+package allure
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/goccy/go-json"
+)
+
+// sniffLen is the number of bytes used to detect the mime-type of the content
+const sniffLen = 512
+
+var (
+	mimeTypesMu  sync.RWMutex
+	mimeTypeExts = make(map[MimeType]string)
+	extMimeTypes = make(map[string]MimeType)
+)
+
+// RegisterMimeType registers the file extension of the mime-type (e.g. `application/x-ndjson` => `.ndjson`),
+// so attachments of this type get a source file the Allure UI is able to preview.
+// The extension may be passed with or without the leading dot. Registered extensions take precedence over built-in ones.
+func RegisterMimeType(mimeType MimeType, ext string) {
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+
+	mimeTypesMu.Lock()
+	defer mimeTypesMu.Unlock()
+
+	mimeTypeExts[mimeType.base()] = ext
+	extMimeTypes[strings.ToLower(ext)] = mimeType
+}
+
+// DetectMimeType detects the mime-type of the content by its first bytes.
+// Returns `application/octet-stream` if the type can't be detected.
+func DetectMimeType(content []byte) MimeType {
+	if len(content) > sniffLen {
+		content = content[:sniffLen]
+	}
+
+	detected := MimeType(http.DetectContentType(content)).base()
+
+	switch detected {
+	case "text/plain":
+		if trimmed := strings.TrimSpace(string(content)); strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+			if json.Valid([]byte(trimmed)) {
+				return JSON
+			}
+		}
+
+		return Text
+	case "text/xml":
+		return XML
+	case "image/jpeg":
+		return Jpg
+	case "application/ogg":
+		return Ogg
+	default:
+		return detected
+	}
+}
+
+// mimeTypeByExt returns the mime-type of the file extension (registered ones first) and true if it is known
+func mimeTypeByExt(ext string) (MimeType, bool) {
+	ext = strings.ToLower(ext)
+
+	mimeTypesMu.RLock()
+	mimeType, ok := extMimeTypes[ext]
+	mimeTypesMu.RUnlock()
+
+	if ok {
+		return mimeType, true
+	}
+
+	if ext == "" {
+		return "", false
+	}
+
+	if detected := mime.TypeByExtension(ext); detected != "" {
+		return MimeType(detected).base(), true
+	}
+
+	return "", false
+}
+
+// registeredExt returns the registered file extension of the mime-type and true if it is registered
+func (mt MimeType) registeredExt() (string, bool) {
+	mimeTypesMu.RLock()
+	defer mimeTypesMu.RUnlock()
+
+	ext, ok := mimeTypeExts[mt.base()]
+
+	return ext, ok
+}
+
+// base returns the mime-type without parameters (e.g. `text/plain; charset=utf-8` => `text/plain`)
+func (mt MimeType) base() MimeType {
+	base := string(mt)
+	if idx := strings.IndexByte(base, ';'); idx >= 0 {
+		base = base[:idx]
+	}
+
+	return MimeType(strings.ToLower(strings.TrimSpace(base)))
+}
+
+// detectFileMimeType detects the mime-type of the file by its extension or by its first bytes
+func detectFileMimeType(path string) MimeType {
+	if mimeType, ok := mimeTypeByExt(filepath.Ext(path)); ok {
+		return mimeType
+	}
+
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	head := make([]byte, sniffLen)
+	n, _ := io.ReadFull(file, head)
+
+	return DetectMimeType(head[:n])
+}
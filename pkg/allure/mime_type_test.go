@@ -0,0 +1,84 @@
+package allure
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterMimeType(t *testing.T) {
+	const ndjson MimeType = "application/x-ndjson"
+
+	RegisterMimeType(ndjson, "ndjson")
+	require.Equal(t, ".ndjson", ndjson.Ext())
+	require.Equal(t, ".ndjson", MimeType("application/x-ndjson; charset=utf-8").Ext())
+
+	mimeType, ok := mimeTypeByExt(".NDJSON")
+	require.True(t, ok)
+	require.Equal(t, ndjson, mimeType)
+
+	RegisterMimeType(Text, ".log")
+	defer RegisterMimeType(Text, ".txt")
+	require.Equal(t, ".log", Text.Ext())
+
+	// the unknown types don't use the mime-types table of the host
+	require.Empty(t, MimeType("application/zip").Ext())
+}
+
+func TestDetectMimeType(t *testing.T) {
+	tests := map[string]struct {
+		content  []byte
+		expected MimeType
+	}{
+		"text":  {[]byte("some content"), Text},
+		"json":  {[]byte(` {"key": ["value"]}`), JSON},
+		"xml":   {[]byte(`<?xml version="1.0"?><key>value</key>`), XML},
+		"html":  {[]byte(`<html><body>value</body></html>`), HTML},
+		"png":   {[]byte("\x89PNG\x0D\x0A\x1A\x0A"), Png},
+		"jpg":   {[]byte("\xFF\xD8\xFF"), Jpg},
+		"pdf":   {[]byte("%PDF-"), Pdf},
+		"zip":   {[]byte("PK\x03\x04"), "application/zip"},
+		"bytes": {[]byte{0x00, 0x01, 0x02}, "application/octet-stream"},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.expected, DetectMimeType(tt.content))
+		})
+	}
+}
+
+func TestNewAttachment_detectMimeType(t *testing.T) {
+	attachment := NewAttachment("json", "", []byte(`{"key": "value"}`))
+	require.Equal(t, JSON, attachment.Type)
+	require.True(t, strings.HasSuffix(attachment.Source, JSON.Ext()))
+}
+
+func TestNewAttachmentFromReader_detectMimeType(t *testing.T) {
+	SetSink(NewMemorySink())
+	defer SetSink(nil)
+
+	attachment, err := NewAttachmentFromReader("png", "", strings.NewReader("\x89PNG\x0D\x0A\x1A\x0Asome content"))
+	require.NoError(t, err)
+	require.Equal(t, Png, attachment.Type)
+	require.True(t, strings.HasSuffix(attachment.Source, Png.Ext()))
+
+	content, ok := GetSink().(*MemorySink).File(attachment.Source)
+	require.True(t, ok)
+	require.Equal(t, "\x89PNG\x0D\x0A\x1A\x0Asome content", string(content))
+}
+
+func TestDetectFileMimeType(t *testing.T) {
+	dir := t.TempDir()
+
+	RegisterMimeType("text/markdown", ".md")
+	require.Equal(t, MimeType("text/markdown"), detectFileMimeType(filepath.Join(dir, "README.md")))
+
+	path := filepath.Join(dir, "no_extension")
+	require.NoError(t, os.WriteFile(path, []byte(`{"key": "value"}`), fileSystemPermissionCode))
+	require.Equal(t, JSON, detectFileMimeType(path))
+}
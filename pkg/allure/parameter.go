@@ -2,6 +2,7 @@ package allure
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -16,8 +17,28 @@ import (
 type Parameter struct {
 	Name  string      `json:"name"`
 	Value interface{} `json:"value"`
+
+	mode     ParameterMode // How Allure shows the parameter, see ParameterMode
+	excluded bool          // If true - the parameter is ignored by the test history
 }
 
+// ParameterMode defines how Allure shows the parameter in the report
+type ParameterMode string
+
+// ParameterMode constants
+const (
+	ParameterModeDefault ParameterMode = "default" // The value is shown as is
+	ParameterModeMasked  ParameterMode = "masked"  // The value is replaced with MaskedValue, it never gets into the result files
+	ParameterModeHidden  ParameterMode = "hidden"  // The parameter is not shown in the report, but it is written and used by the history
+)
+
+// MaskedValue replaces the value of the masked parameter
+const MaskedValue = "******"
+
+// minContainedMaskedLen is the minimal length of the masked value searched inside the other values.
+// The shorter values (e.g. "1" or "ok") mask only the parameters with the same value.
+const minContainedMaskedLen = 6
+
 // NewParameter Constructor. Builds and returns a new `Parameter` object,
 // using `name` as the parameter name and `value`, as the value.
 func NewParameter(name string, value ...interface{}) *Parameter {
@@ -47,8 +68,105 @@ func NewParameters(kv ...interface{}) []*Parameter {
 	return result
 }
 
-// GetValue returns param value as string
+// Masked sets the ParameterModeMasked mode: the value is replaced with MaskedValue in the result files,
+// the history and the text output. The value is also masked in the assertion parameters of the same test containing it
+// (see MaskKnownValues). Returns a pointer to the current Parameter (for Fluent Interface).
+func (p *Parameter) Masked() *Parameter {
+	p.mode = ParameterModeMasked
+
+	return p
+}
+
+// Hidden sets the ParameterModeHidden mode: the parameter is not shown in the report.
+// Returns a pointer to the current Parameter (for Fluent Interface).
+func (p *Parameter) Hidden() *Parameter {
+	p.mode = ParameterModeHidden
+
+	return p
+}
+
+// Excluded excludes the parameter from the history ID, so the results with the different values of the parameter
+// are the retries of the same test.
+// Returns a pointer to the current Parameter (for Fluent Interface).
+func (p *Parameter) Excluded() *Parameter {
+	p.excluded = true
+
+	return p
+}
+
+// GetMode returns the mode of the parameter, an empty mode is the same as ParameterModeDefault
+func (p *Parameter) GetMode() ParameterMode {
+	return p.mode
+}
+
+// IsMasked returns true if the parameter has ParameterModeMasked mode
+func (p *Parameter) IsMasked() bool {
+	return p.mode == ParameterModeMasked
+}
+
+// IsExcluded returns true if the parameter is excluded from the history ID
+func (p *Parameter) IsExcluded() bool {
+	return p.excluded
+}
+
+// MaskKnownValues masks the parameters whose values contain any of the masked values
+// (e.g. the `Expected` and `Actual` parameters of the assertion comparing the token of the test, see Result.MaskedValues).
+// The masked values shorter than 6 characters mask only the parameters with the same value.
+// Returns the passed parameters.
+func MaskKnownValues(masked []string, params ...*Parameter) []*Parameter {
+	if len(masked) == 0 {
+		return params
+	}
+
+	for _, param := range params {
+		if param == nil || param.IsMasked() {
+			continue
+		}
+
+		value := param.rawValue()
+
+		for _, maskedValue := range masked {
+			if value == maskedValue || (len(maskedValue) >= minContainedMaskedLen && strings.Contains(value, maskedValue)) {
+				param.mode = ParameterModeMasked
+				break
+			}
+		}
+	}
+
+	return params
+}
+
+// maskedValuesOf appends the values of the masked parameters and of the masked parameters of the steps to values
+func maskedValuesOf(values []string, params []*Parameter, steps []*Step) []string {
+	for _, param := range params {
+		if param == nil || !param.IsMasked() {
+			continue
+		}
+
+		if value := param.rawValue(); value != "" && value != MaskedValue {
+			values = append(values, value)
+		}
+	}
+
+	for _, step := range steps {
+		if step != nil {
+			values = maskedValuesOf(values, step.Parameters, step.Steps)
+		}
+	}
+
+	return values
+}
+
+// GetValue returns param value as string. The value of the masked parameter is MaskedValue.
 func (p *Parameter) GetValue() string {
+	if p.IsMasked() {
+		return MaskedValue
+	}
+
+	return p.rawValue()
+}
+
+func (p *Parameter) rawValue() string {
 	s := fmt.Sprint(p.Value)
 
 	unquoted, err := strconv.Unquote(s)
@@ -169,8 +287,10 @@ func (p *Parameter) UnmarshalJSON(data []byte) error {
 	// TODO: refactor this in v2
 
 	var aux struct {
-		Name  string         `json:"name"`
-		Value parameterValue `json:"value"`
+		Name     string         `json:"name"`
+		Value    parameterValue `json:"value"`
+		Mode     ParameterMode  `json:"mode"`
+		Excluded bool           `json:"excluded"`
 	}
 
 	if err := json.Unmarshal(data, &aux); err != nil {
@@ -178,17 +298,25 @@ func (p *Parameter) UnmarshalJSON(data []byte) error {
 	}
 
 	*p = Parameter{
-		Name:  aux.Name,
-		Value: aux.Value.Inner(),
+		Name:     aux.Name,
+		Value:    aux.Value.Inner(),
+		mode:     aux.Mode,
+		excluded: aux.Excluded,
 	}
 
 	return nil
 }
 
+// MarshalJSON marshals the parameter, the value of the masked parameter is written as MaskedValue
 func (p *Parameter) MarshalJSON() ([]byte, error) {
 	var raw json.RawMessage
 
-	switch v := p.Value.(type) {
+	value := p.Value
+	if p.IsMasked() {
+		value = MaskedValue
+	}
+
+	switch v := value.(type) {
 	case proto.Message:
 		res, err := protojson.MarshalOptions{
 			AllowPartial:      true,
@@ -209,16 +337,43 @@ func (p *Parameter) MarshalJSON() ([]byte, error) {
 	}
 
 	aux := struct {
-		Name  string          `json:"name"`
-		Value json.RawMessage `json:"value"`
+		Name     string          `json:"name"`
+		Value    json.RawMessage `json:"value"`
+		Mode     ParameterMode   `json:"mode,omitempty"`
+		Excluded bool            `json:"excluded,omitempty"`
 	}{
-		Name:  p.Name,
-		Value: raw,
+		Name:     p.Name,
+		Value:    raw,
+		Mode:     p.mode,
+		Excluded: p.excluded,
 	}
 
 	return json.Marshal(aux)
 }
 
+// parametersHash returns the hash of the parameters taking part in the history:
+// the excluded parameters are skipped and the masked ones are hashed by the name only.
+// Returns an empty string if there are no such parameters.
+func parametersHash(params []*Parameter) string {
+	pairs := make([]string, 0, len(params))
+
+	for _, param := range params {
+		if param == nil || param.excluded {
+			continue
+		}
+
+		pairs = append(pairs, param.Name+"="+param.GetValue())
+	}
+
+	if len(pairs) == 0 {
+		return ""
+	}
+
+	sort.Strings(pairs)
+
+	return getMD5Hash(strings.Join(pairs, "\n"))
+}
+
 func trimBrackets(val string) string {
 	if strings.HasSuffix(val, "]") && strings.HasPrefix(val, "[") {
 		return strings.TrimSuffix(strings.TrimPrefix(val, "["), "]")
@@ -246,3 +401,18 @@ func messageFromMsgAndArgs(msgAndArgs ...interface{}) string {
 		return fmt.Sprintf(msgAndArgs[0].(string), msgAndArgs[1:]...)
 	}
 }
+
+// copyParameters returns the copies of the parameters, the values are shared
+func copyParameters(parameters []*Parameter) []*Parameter {
+	if parameters == nil {
+		return nil
+	}
+
+	copied := make([]*Parameter, 0, len(parameters))
+	for _, parameter := range parameters {
+		parameterCopy := *parameter
+		copied = append(copied, &parameterCopy)
+	}
+
+	return copied
+}
@@ -136,3 +136,72 @@ func TestParameterUnmarshal(t *testing.T) {
 		require.Equal(t, "map[a:[1 true 3.14]]", param.GetValue())
 	})
 }
+
+func TestParameter_Masked(t *testing.T) {
+	param := NewParameter("password", "parameter-secret").Masked()
+	require.True(t, param.IsMasked())
+	require.Equal(t, MaskedValue, param.GetValue())
+	require.Equal(t, "parameter-secret", param.Value)
+
+	bytes, err := json.Marshal(param)
+	require.NoError(t, err)
+	require.NotContains(t, string(bytes), "parameter-secret")
+	require.JSONEq(t, `{"name":"password","value":"******","mode":"masked"}`, string(bytes))
+
+	var newParam Parameter
+	require.NoError(t, json.Unmarshal(bytes, &newParam))
+	require.Equal(t, Parameter{Name: "password", Value: MaskedValue, mode: ParameterModeMasked}, newParam)
+}
+
+func TestParameter_HiddenExcluded(t *testing.T) {
+	param := NewParameter("host", "localhost").Hidden().Excluded()
+	require.Equal(t, ParameterModeHidden, param.GetMode())
+	require.True(t, param.IsExcluded())
+	require.Equal(t, "localhost", param.GetValue())
+
+	bytes, err := json.Marshal(param)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"host","value":"localhost","mode":"hidden","excluded":true}`, string(bytes))
+
+	var newParam Parameter
+	require.NoError(t, json.Unmarshal(bytes, &newParam))
+	require.Equal(t, *param, newParam)
+}
+
+func TestMaskKnownValues(t *testing.T) {
+	result := NewResult("test", "TestMaskKnownValues")
+	result.Parameters = append(result.Parameters, NewParameter("token", "known-secret-value").Masked())
+
+	params := MaskKnownValues(result.MaskedValues(), NewParameters("Expected", `"known-secret-value"`, "Actual", "known")...)
+	require.Len(t, params, 2)
+	require.True(t, params[0].IsMasked())
+	require.False(t, params[1].IsMasked())
+
+	// the short values mask only the same values
+	step := NewSimpleStep("login", NewParameter("pin", "42").Masked())
+	result.Steps = append(result.Steps, NewSimpleStep("parent").WithChild(step))
+	require.Equal(t, []string{"42"}, step.MaskedValues())
+
+	params = MaskKnownValues(result.MaskedValues(), NewParameters("Expected", "42", "Actual", "1420")...)
+	require.True(t, params[0].IsMasked())
+	require.False(t, params[1].IsMasked())
+
+	// the values masked by another test are not masked
+	params = MaskKnownValues(NewResult("test", "TestOther").MaskedValues(), NewParameters("Expected", "known-secret-value")...)
+	require.False(t, params[0].IsMasked())
+}
+
+func TestParametersHash(t *testing.T) {
+	require.Empty(t, parametersHash(nil))
+	require.Empty(t, parametersHash([]*Parameter{NewParameter("run", 1).Excluded()}))
+
+	hash := parametersHash(NewParameters("a", "1", "b", "2"))
+	require.NotEmpty(t, hash)
+	require.Equal(t, hash, parametersHash(NewParameters("b", "2", "a", "1")))
+	require.Equal(t, hash, parametersHash(append(NewParameters("b", "2", "a", "1"), NewParameter("run", 2).Excluded())))
+	require.NotEqual(t, hash, parametersHash(NewParameters("a", "1", "b", "3")))
+
+	first := parametersHash([]*Parameter{NewParameter("token", "hash-secret-1").Masked()})
+	second := parametersHash([]*Parameter{NewParameter("token", "hash-secret-2").Masked()})
+	require.Equal(t, first, second)
+}
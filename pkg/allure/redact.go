@@ -0,0 +1,374 @@
+package allure
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+// RedactedValue replaces the redacted secrets
+const RedactedValue = "[REDACTED]"
+
+// redactedLabelValue is the value of the Redacted label added to the result some strings or attachments of which were redacted
+const redactedLabelValue = "true"
+
+// redactor is the process-wide registry of the secrets scrubbed from everything allure-go prints
+type redactor struct {
+	mu       sync.RWMutex
+	values   []string
+	patterns []*regexp.Regexp
+
+	count int64 // number of redacted entities
+}
+
+var redaction = newRedactorFromEnv()
+
+func newRedactorFromEnv() *redactor {
+	r := new(redactor)
+
+	if names := configValue(redactEnvEnvKey); names != "" {
+		r.addEnv(strings.Split(names, ",")...)
+	}
+
+	file := getConfigFile()
+	r.addValues(file.Redaction.Values...)
+
+	for _, pattern := range file.Redaction.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			fmt.Printf("Invalid redaction pattern %q of the config file is ignored: %s\n", pattern, err)
+			continue
+		}
+
+		r.patterns = append(r.patterns, re)
+	}
+
+	return r
+}
+
+// RedactValues registers the secrets that are replaced with RedactedValue in every string of the printed results
+// and containers (names, status details, parameters, labels, links, steps) and in the text attachments.
+// Empty values are ignored.
+func RedactValues(values ...string) {
+	redaction.addValues(values...)
+}
+
+// RedactPatterns registers the regular expressions whose matches are redacted like the values of RedactValues.
+// If any of the patterns doesn't compile, none of them is registered.
+func RedactPatterns(patterns ...string) error {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return errors.Wrapf(err, "Invalid redaction pattern %q", pattern)
+		}
+
+		compiled = append(compiled, re)
+	}
+
+	redaction.mu.Lock()
+	defer redaction.mu.Unlock()
+
+	redaction.patterns = append(redaction.patterns, compiled...)
+
+	return nil
+}
+
+// RedactEnv registers the current values of the environment variables as secrets (see RedactValues).
+// The variables listed in `$ALLURE_REDACT_ENV` (separated by commas) or `redaction.env` of the config file
+// are registered on start, as well as `redaction.values` and `redaction.patterns` of the config file.
+func RedactEnv(names ...string) {
+	redaction.addEnv(names...)
+}
+
+// ResetRedaction removes all registered secrets and resets the RedactionCount
+func ResetRedaction() {
+	redaction.mu.Lock()
+	defer redaction.mu.Unlock()
+
+	redaction.values = nil
+	redaction.patterns = nil
+	atomic.StoreInt64(&redaction.count, 0)
+}
+
+// Redact returns the string with all registered secrets replaced with RedactedValue
+func Redact(s string) string {
+	redacted, _ := redaction.redact(s)
+	return redacted
+}
+
+// RedactionCount returns the number of results, containers, attachments and launch files
+// printed with some secrets redacted
+func RedactionCount() int {
+	return int(atomic.LoadInt64(&redaction.count))
+}
+
+func (r *redactor) addValues(values ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, value := range values {
+		if value != "" {
+			r.values = append(r.values, value)
+		}
+	}
+
+	// the longer secrets are replaced first, so the shorter ones they contain don't leave their parts
+	sort.SliceStable(r.values, func(i, j int) bool {
+		return len(r.values[i]) > len(r.values[j])
+	})
+}
+
+func (r *redactor) addEnv(names ...string) {
+	values := make([]string, 0, len(names))
+
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			values = append(values, os.Getenv(name))
+		}
+	}
+
+	r.addValues(values...)
+}
+
+func (r *redactor) active() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.values) > 0 || len(r.patterns) > 0
+}
+
+// redact returns the string with the secrets replaced and true if any secret was found
+func (r *redactor) redact(s string) (string, bool) {
+	if s == "" {
+		return s, false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	redacted := s
+
+	for _, value := range r.values {
+		redacted = strings.ReplaceAll(redacted, value, RedactedValue)
+	}
+
+	for _, pattern := range r.patterns {
+		redacted = pattern.ReplaceAllLiteralString(redacted, RedactedValue)
+	}
+
+	return redacted, redacted != s
+}
+
+// redactBytes is redact for the content of the text attachments
+func (r *redactor) redactBytes(content []byte) ([]byte, bool) {
+	if len(content) == 0 {
+		return content, false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	redacted := content
+
+	for _, value := range r.values {
+		redacted = bytes.ReplaceAll(redacted, []byte(value), []byte(RedactedValue))
+	}
+
+	for _, pattern := range r.patterns {
+		redacted = pattern.ReplaceAllLiteral(redacted, []byte(RedactedValue))
+	}
+
+	return redacted, !bytes.Equal(redacted, content)
+}
+
+func (r *redactor) record() {
+	atomic.AddInt64(&r.count, 1)
+}
+
+// scrubber redacts the strings of one printed entity in place and remembers if anything was redacted
+type scrubber struct {
+	r        *redactor
+	redacted bool
+}
+
+func (s *scrubber) str(str *string) {
+	if redacted, ok := s.r.redact(*str); ok {
+		*str = redacted
+		s.redacted = true
+	}
+}
+
+// value redacts the interface{} value of the label or the parameter, it becomes a string if it contains a secret
+func (s *scrubber) value(value *interface{}, str string) {
+	if redacted, ok := s.r.redact(str); ok {
+		*value = redacted
+		s.redacted = true
+	}
+}
+
+func (s *scrubber) details(details *StatusDetail) {
+	s.str(&details.Message)
+	s.str(&details.Trace)
+}
+
+func (s *scrubber) parameters(params []*Parameter) {
+	for _, param := range params {
+		if param == nil {
+			continue
+		}
+
+		s.str(&param.Name)
+		s.value(&param.Value, param.rawValue())
+	}
+}
+
+func (s *scrubber) attachments(attachments []*Attachment) {
+	for _, attachment := range attachments {
+		if attachment == nil {
+			continue
+		}
+
+		s.str(&attachment.Name)
+
+		if attachment.redacted {
+			s.redacted = true
+		}
+	}
+}
+
+func (s *scrubber) steps(steps []*Step) {
+	for _, step := range steps {
+		if step == nil {
+			continue
+		}
+
+		s.str(&step.Name)
+		s.str(&step.ExpectedResult)
+		s.details(&step.StatusDetails)
+		s.parameters(step.Parameters)
+		s.attachments(step.Attachments)
+		s.steps(step.Steps)
+		s.steps(step.ExpectedSteps)
+	}
+}
+
+// redact scrubs the registered secrets from the result before it is written.
+// If anything was redacted, the result gets the `redacted` label.
+func (result *Result) redact() {
+	if !redaction.active() {
+		return
+	}
+
+	result.m.Lock()
+	defer result.m.Unlock()
+
+	s := &scrubber{r: redaction}
+
+	s.str(&result.Name)
+	s.str(&result.FullName)
+	s.str(&result.Description)
+	s.str(&result.ExpectedResult)
+	s.details(&result.StatusDetails)
+	s.parameters(result.Parameters)
+	s.attachments(result.Attachments)
+	s.steps(result.Steps)
+	s.steps(result.ExpectedSteps)
+
+	for _, label := range result.Labels {
+		if label != nil {
+			s.value(&label.Value, label.GetValue())
+		}
+	}
+
+	for _, link := range result.Links {
+		if link != nil {
+			s.str(&link.Name)
+			s.str(&link.URL)
+		}
+	}
+
+	if !s.redacted {
+		return
+	}
+
+	redaction.record()
+
+	for _, label := range result.Labels {
+		if label != nil && label.Name == Redacted.String() {
+			return
+		}
+	}
+
+	result.Labels = append(result.Labels, NewLabel(Redacted, redactedLabelValue))
+}
+
+// redact scrubs the registered secrets from the steps of the container before it is written
+func (container *Container) redact() {
+	if !redaction.active() {
+		return
+	}
+
+	s := &scrubber{r: redaction}
+
+	s.steps(container.Befores)
+	s.steps(container.Afters)
+
+	if s.redacted {
+		redaction.record()
+	}
+}
+
+// isText returns true if the mime-type is a text one, whose content is redacted
+func (mt MimeType) isText() bool {
+	base := mt.base()
+
+	switch base {
+	case XML, JSON, Yaml, "application/x-yaml", "application/javascript", "application/x-ndjson":
+		return true
+	}
+
+	return strings.HasPrefix(string(base), "text/") ||
+		strings.HasSuffix(string(base), "+json") ||
+		strings.HasSuffix(string(base), "+xml")
+}
+
+// shouldRedact returns true if the content of the attachment must be redacted before it is written
+func (a *Attachment) shouldRedact() bool {
+	return a.Type.isText() && redaction.active()
+}
+
+// redactContent returns the content with the secrets redacted and remembers if anything was redacted
+func (a *Attachment) redactContent(content []byte) []byte {
+	redacted, ok := redaction.redactBytes(content)
+	if ok {
+		a.redacted = true
+		redaction.record()
+	}
+
+	return redacted
+}
+
+// writeFrom writes the content of the attachment from the reader to the sink.
+// The text content is read into memory and redacted if any secrets are registered, otherwise it is streamed.
+func (a *Attachment) writeFrom(sink Sink, content io.Reader) error {
+	if !a.shouldRedact() {
+		return createFileFrom(sink, a.Source, content)
+	}
+
+	bContent, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+
+	return sink.CreateFile(a.Source, a.redactContent(bContent))
+}
@@ -0,0 +1,176 @@
+package allure
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedact(t *testing.T) {
+	defer ResetRedaction()
+
+	require.Equal(t, "token secret-token", Redact("token secret-token"))
+
+	RedactValues("secret", "secret-token", "")
+	require.NoError(t, RedactPatterns(`Bearer \w+`))
+	require.Error(t, RedactPatterns(`Basic (`))
+
+	require.Equal(t, "token [REDACTED], [REDACTED] again", Redact("token secret-token, secret again"))
+	require.Equal(t, "Authorization: [REDACTED]", Redact("Authorization: Bearer abc123"))
+	require.Equal(t, "nothing here", Redact("nothing here"))
+
+	ResetRedaction()
+	require.Equal(t, "secret", Redact("secret"))
+}
+
+func TestRedactEnv(t *testing.T) {
+	defer ResetRedaction()
+
+	t.Setenv("ALLURE_TEST_PASSWORD", "env-password")
+	RedactEnv("ALLURE_TEST_PASSWORD", "ALLURE_TEST_NOT_SET")
+
+	require.Equal(t, "password=[REDACTED]", Redact("password=env-password"))
+	require.Equal(t, "empty", Redact("empty"))
+
+	t.Setenv(redactEnvEnvKey, "ALLURE_TEST_PASSWORD, ALLURE_TEST_NOT_SET")
+	r := newRedactorFromEnv()
+	redacted, ok := r.redact("env-password")
+	require.True(t, ok)
+	require.Equal(t, RedactedValue, redacted)
+}
+
+func TestResult_Print_redacted(t *testing.T) {
+	defer ResetRedaction()
+	RedactValues("result-secret")
+
+	sink := NewMemorySink()
+	attachment := NewAttachment("response", JSON, []byte(`{"token":"result-secret"}`))
+	binary := NewAttachment("image", Png, []byte("result-secret"))
+
+	result := NewResult("login with result-secret", "login").WithSink(sink)
+	result.StatusDetails = StatusDetail{Message: "bad token result-secret", Trace: "trace result-secret"}
+	result.Parameters = NewParameters("token", "result-secret")
+	result.Labels = append(result.Labels, NewLabel(Tag, "result-secret"))
+	result.Links = append(result.Links, &Link{Name: "link", URL: "http://host/?token=result-secret"})
+	result.Steps = append(result.Steps, NewSimpleStep("step",
+		NewParameters("Expected", "result-secret", "Actual", "other")...,
+	).WithAttachments(attachment, binary))
+
+	before := RedactionCount()
+	require.NoError(t, result.Print())
+	require.Equal(t, before+2, RedactionCount())
+
+	content, ok := sink.File(result.UUID.String() + resultFileSuffix)
+	require.True(t, ok)
+	require.NotContains(t, string(content), "result-secret")
+	require.Equal(t, "login with [REDACTED]", result.Name)
+	require.Equal(t, "[REDACTED]", result.Steps[0].Parameters[0].GetValue())
+	require.Equal(t, "other", result.Steps[0].Parameters[1].GetValue())
+
+	label, ok := result.GetFirstLabel(Redacted)
+	require.True(t, ok)
+	require.Equal(t, "true", label.GetValue())
+
+	content, ok = sink.File(attachment.Source)
+	require.True(t, ok)
+	require.Equal(t, `{"token":"[REDACTED]"}`, string(content))
+
+	content, ok = sink.File(binary.Source)
+	require.True(t, ok)
+	require.Equal(t, "result-secret", string(content))
+}
+
+func TestResult_Print_notRedacted(t *testing.T) {
+	defer ResetRedaction()
+	RedactValues("result-secret")
+
+	result := NewResult("login", "login").WithSink(NewMemorySink())
+	require.NoError(t, result.Print())
+
+	_, ok := result.GetFirstLabel(Redacted)
+	require.False(t, ok)
+}
+
+func TestContainer_Print_redacted(t *testing.T) {
+	defer ResetRedaction()
+	RedactValues("container-secret")
+
+	sink := NewMemorySink()
+	container := NewContainer().WithSink(sink)
+	container.Befores = append(container.Befores, NewSimpleStep("setup container-secret"))
+
+	require.NoError(t, container.Print())
+
+	content, ok := sink.File(container.UUID.String() + containerFileSuffix)
+	require.True(t, ok)
+	require.NotContains(t, string(content), "container-secret")
+	require.Equal(t, "setup [REDACTED]", container.Befores[0].Name)
+}
+
+func TestAttachment_redacted(t *testing.T) {
+	defer ResetRedaction()
+	RedactValues("attachment-secret")
+
+	sink := NewMemorySink()
+	SetSink(sink)
+	defer SetSink(nil)
+
+	fromReader, err := NewAttachmentFromReader("reader", Text, strings.NewReader("reader attachment-secret"))
+	require.NoError(t, err)
+
+	content, ok := sink.File(fromReader.Source)
+	require.True(t, ok)
+	require.Equal(t, "reader [REDACTED]", string(content))
+	require.True(t, fromReader.redacted)
+
+	lazy := NewLazyAttachment("lazy", Text, func(w io.Writer) error {
+		_, err := io.WriteString(w, "lazy attachment-secret")
+		return err
+	})
+	require.NoError(t, lazy.Print())
+
+	content, ok = sink.File(lazy.Source)
+	require.True(t, ok)
+	require.Equal(t, "lazy [REDACTED]", string(content))
+
+	dir := t.TempDir()
+	SetSink(NewFileSink(filepath.Join(dir, "allure-results")))
+
+	path := filepath.Join(dir, "some.log")
+	require.NoError(t, os.WriteFile(path, []byte("file attachment-secret"), fileSystemPermissionCode))
+
+	fromFile, err := NewAttachmentFromFile("file", Text, path, MoveFile)
+	require.NoError(t, err)
+
+	content, err = os.ReadFile(filepath.Join(dir, "allure-results", fromFile.Source))
+	require.NoError(t, err)
+	require.Equal(t, "file [REDACTED]", string(content))
+
+	_, err = os.Stat(path)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestEnvironment_Print_redacted(t *testing.T) {
+	defer ResetRedaction()
+	RedactValues("environment-secret")
+
+	sink := NewMemorySink()
+	require.NoError(t, NewEnvironment().With("token", "environment-secret").WithSink(sink).Print())
+
+	content, ok := sink.File(environmentFileName)
+	require.True(t, ok)
+	require.Equal(t, "token=[REDACTED]\n", string(content))
+}
+
+func TestMimeType_isText(t *testing.T) {
+	require.True(t, Text.isText())
+	require.True(t, MimeType("text/plain; charset=utf-8").isText())
+	require.True(t, JSON.isText())
+	require.True(t, MimeType("application/problem+json").isText())
+	require.False(t, Png.isText())
+	require.False(t, Pdf.isText())
+}
@@ -3,8 +3,6 @@ package allure
 import (
 	"crypto/md5"
 	"encoding/hex"
-	"fmt"
-	"os"
 	"runtime"
 	"strings"
 	"sync"
@@ -30,7 +28,12 @@ type Result struct {
 	TestCaseID    string       `json:"testCaseId,omitempty"`    // ID of the test case (based on the hash of the full call)
 	Description   string       `json:"description,omitempty"`   // Test description
 
-	m sync.RWMutex
+	m    sync.RWMutex
+	sink Sink
+
+	idStrategy          IDStrategy
+	generatedTestCaseID string // TestCaseID computed by the IDStrategy, it is recomputed unless changed
+	generatedHistoryID  string // HistoryID computed by the IDStrategy, it is recomputed unless changed
 
 	Attachments    []*Attachment `json:"attachments,omitempty"`    // Test case attachments
 	Parameters     []*Parameter  `json:"parameters,omitempty"`     // Test case parameters
@@ -50,24 +53,25 @@ type Result struct {
 // |UUID       | random `uuid4` value             |
 // |Name       | testName from args               |
 // |FullName   | fullName from args               |
-// |TestCaseID | md5 hash of `Result.FullName`    |
-// |HistoryID  | md5 hash from `Result.TestCaseID`|
+// |TestCaseID | computed by the IDStrategy       |
+// |HistoryID  | computed by the IDStrategy       |
 // |Container  | new empty `allure.Container`     |
 // |Labels     | add new `allure.Language` label  |
 // |Start      | allure.GetNow()                  |
 // |ToPrint    | `true`                           |
 // ================================================
 // Sets the child for the container object.
+// The IDs are computed by the process-wide IDStrategy (see SetIDStrategy) and recomputed on Print,
+// when the parameters and the labels of the result are known.
 func NewResult(testName, fullName string) *Result {
 	result := Result{
-		UUID:       uuid.New(),
-		Name:       testName,
-		FullName:   fullName,
-		TestCaseID: getMD5Hash(fullName),
-		ToPrint:    true,
+		UUID:     uuid.New(),
+		Name:     testName,
+		FullName: fullName,
+		ToPrint:  true,
 	}
 
-	result.HistoryID = getMD5Hash(result.TestCaseID)
+	result.UpdateIDs()
 	result.AddLabel(LanguageLabel(runtime.Version()))
 	result.Begin()
 
@@ -90,6 +94,11 @@ func (result *Result) GetStatusTrace() string {
 	return result.StatusDetails.Trace
 }
 
+// MaskedValues returns the values of the masked parameters of the result and of its steps (see MaskKnownValues)
+func (result *Result) MaskedValues() []string {
+	return maskedValuesOf(nil, result.Parameters, result.Steps)
+}
+
 // AddLabel Adds all passed in arguments `allure.Label` to the report
 func (result *Result) AddLabel(labels ...*Label) {
 	result.m.Lock()
@@ -133,6 +142,19 @@ func (result *Result) SetNewLabelMap(kv map[LabelType]string) {
 	result.AddLabel(labels...)
 }
 
+// WithSink sets the Sink the result and its attachments will be printed to.
+// If sink is nil, the process-wide Sink is used (see SetSink).
+// Returns a pointer to the current `allure.Result` (for Fluent Interface).
+func (result *Result) WithSink(sink Sink) *Result {
+	result.sink = sink
+	return result
+}
+
+// GetSink returns the Sink the result and its attachments are printed to, the process-wide Sink if the result has no own one
+func (result *Result) GetSink() Sink {
+	return sinkOrDefault(result.sink)
+}
+
 // WithStage sets Stage field to result
 // Returns a pointer to the current `allure.Result` (for Fluent Interface).
 func (result *Result) WithStage(stage string) *Result {
@@ -218,7 +240,7 @@ func (result *Result) WithLabels(labels ...*Label) *Result {
 // WithLaunchTags Adds all Launch Tags from the global variable `ALLURE_LAUNCH_TAGS` as labels with type `Tag` to the report.
 // Returns a pointer to the current `allure.Result` (for Fluent Interface).
 func (result *Result) WithLaunchTags() *Result {
-	tags := os.Getenv(defaultTagsEnvKey)
+	tags := configValue(defaultTagsEnvKey)
 	if tags == "" {
 		return result
 	}
@@ -233,6 +255,93 @@ func (result *Result) WithLaunchTags() *Result {
 	return result
 }
 
+// Retry returns the result of the next attempt of the test. It has the same names, description, IDs, labels, links
+// and parameters, so it shares the history with the current result, but a new UUID and no status, steps or attachments.
+// The new result starts at the current time.
+func (result *Result) Retry() *Result {
+	result.m.RLock()
+	defer result.m.RUnlock()
+
+	retry := &Result{
+		Name:           result.Name,
+		FullName:       result.FullName,
+		UUID:           uuid.New(),
+		HistoryID:      result.HistoryID,
+		TestCaseID:     result.TestCaseID,
+		Description:    result.Description,
+		ExpectedResult: result.ExpectedResult,
+		ToPrint:        result.ToPrint,
+
+		sink:                result.sink,
+		idStrategy:          result.idStrategy,
+		generatedTestCaseID: result.generatedTestCaseID,
+		generatedHistoryID:  result.generatedHistoryID,
+	}
+
+	// the attempts don't share the labels, links and parameters, which may be changed by the test
+	for _, label := range result.Labels {
+		copied := *label
+		retry.Labels = append(retry.Labels, &copied)
+	}
+
+	for _, link := range result.Links {
+		copied := *link
+		retry.Links = append(retry.Links, &copied)
+	}
+
+	for _, param := range result.Parameters {
+		copied := *param
+		retry.Parameters = append(retry.Parameters, &copied)
+	}
+
+	return retry.Begin()
+}
+
+// Snapshot returns the deep copy of the result. The copy doesn't share the steps, attachments, labels, links
+// and parameters with the result, so it is printed safely while the result is still changed (e.g. by the timed out test).
+func (result *Result) Snapshot() *Result {
+	result.m.RLock()
+	defer result.m.RUnlock()
+
+	snapshot := &Result{
+		Name:           result.Name,
+		FullName:       result.FullName,
+		Stage:          result.Stage,
+		Status:         result.Status,
+		StatusDetails:  result.StatusDetails,
+		Start:          result.Start,
+		Stop:           result.Stop,
+		UUID:           result.UUID,
+		HistoryID:      result.HistoryID,
+		TestCaseID:     result.TestCaseID,
+		Description:    result.Description,
+		ExpectedResult: result.ExpectedResult,
+		ToPrint:        result.ToPrint,
+
+		sink:                result.sink,
+		idStrategy:          result.idStrategy,
+		generatedTestCaseID: result.generatedTestCaseID,
+		generatedHistoryID:  result.generatedHistoryID,
+
+		Attachments:   copyAttachments(result.Attachments),
+		Parameters:    copyParameters(result.Parameters),
+		Steps:         copySteps(result.Steps, nil),
+		ExpectedSteps: copySteps(result.ExpectedSteps, nil),
+	}
+
+	for _, label := range result.Labels {
+		copied := *label
+		snapshot.Labels = append(snapshot.Labels, &copied)
+	}
+
+	for _, link := range result.Links {
+		copied := *link
+		snapshot.Links = append(snapshot.Links, &copied)
+	}
+
+	return snapshot
+}
+
 // Begin Sets `Result.Start` as the current time
 func (result *Result) Begin() *Result {
 	result.Start = GetNow()
@@ -253,28 +362,87 @@ func (result *Result) SkipOnPrint() {
 }
 
 // Print If `Result.ToPrint` = `true` - the method terminates without creating any files. Otherwise:
-//   - Calls `Result.PrintAttachments()`.
+//   - Prints all attachments of the result and its steps.
+//   - Recomputes the IDs of the result by its IDStrategy (with ParametersIDStrategy, the not excluded `Result.Parameters`
+//     are added to the `Result.HistoryID`, so the results of the test with different parameters have different histories).
+//   - Redacts the registered secrets (see RedactValues) and adds the `redacted` label if any were found.
+//   - Validates the result according to the process-wide ValidationMode (see SetValidationMode).
 //   - Saves the file `uuid4-Result.json`.
-//   - Calls `Result.Container.Print()`
-//   - Returns error (if any)
+//   - Returns all occurred errors (if any)
 func (result *Result) Print() error {
 	if !result.ToPrint {
 		return nil
 	}
 
-	result.PrintAttachments()
+	errs := result.printAttachments()
+
+	result.UpdateIDs()
+	result.redact()
+
+	if GetValidationMode() != ValidationOff {
+		if err := applyValidation(result.validate(sinkOrDefault(result.sink))); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := result.printResult(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return joinErrors(errs)
+}
+
+// WithIDStrategy sets the IDStrategy of the result and recomputes its IDs.
+// If strategy is nil, the process-wide IDStrategy is used (see SetIDStrategy).
+// Returns a pointer to the current `allure.Result` (for Fluent Interface).
+func (result *Result) WithIDStrategy(strategy IDStrategy) *Result {
+	result.m.Lock()
+	result.idStrategy = strategy
+	result.m.Unlock()
+
+	result.UpdateIDs()
+
+	return result
+}
+
+// UpdateIDs recomputes the TestCaseID and the HistoryID by the IDStrategy of the result.
+// The IDs changed since they were computed (e.g. set manually) are kept.
+func (result *Result) UpdateIDs() {
+	result.m.RLock()
+	strategy := result.idStrategy
+	updateTestCaseID := result.TestCaseID == result.generatedTestCaseID
+	updateHistoryID := result.HistoryID == result.generatedHistoryID
+	result.m.RUnlock()
+
+	if strategy == nil {
+		strategy = GetIDStrategy()
+	}
+
+	if updateTestCaseID {
+		testCaseID := strategy.TestCaseID(result)
+
+		result.m.Lock()
+		result.TestCaseID, result.generatedTestCaseID = testCaseID, testCaseID
+		result.m.Unlock()
+	}
+
+	if updateHistoryID {
+		historyID := strategy.HistoryID(result)
 
-	return result.printResult()
+		result.m.Lock()
+		result.HistoryID, result.generatedHistoryID = historyID, historyID
+		result.m.Unlock()
+	}
 }
 
-// printResult marshals allure.Result to json and do [os.WriteFile]
+// printResult marshals allure.Result to json and writes it to the result's Sink
 func (result *Result) printResult() error {
 	bResult, err := json.Marshal(result)
 	if err != nil {
 		return errors.Wrap(err, "Failed marshal Result")
 	}
 
-	err = NewFileManager().CreateFile(fmt.Sprintf("%s-result.json", result.UUID), bResult)
+	err = sinkOrDefault(result.sink).CreateFile(result.UUID.String()+resultFileSuffix, bResult)
 	if err != nil {
 		return errors.Wrap(err, "Cannot save Result")
 	}
@@ -286,16 +454,29 @@ func (result *Result) printResult() error {
 // for each allure.Step calls the `Step.PrintAttachments()` method.
 // Then calls `Attachment.Print()` on all `allure.Attachment` of the `Result.Attachments` list.
 func (result *Result) PrintAttachments() {
+	_ = result.printAttachments()
+}
+
+func (result *Result) printAttachments() []error {
 	result.m.RLock()
 	defer result.m.RUnlock()
 
+	var (
+		sink = sinkOrDefault(result.sink)
+		errs []error
+	)
+
 	for _, step := range result.Steps {
-		step.PrintAttachments()
+		errs = append(errs, step.printAttachments(sink)...)
 	}
 
 	for _, attachment := range result.Attachments {
-		_ = attachment.Print()
+		if err := attachment.printTo(sink); err != nil {
+			errs = append(errs, err)
+		}
 	}
+
+	return errs
 }
 
 // Done Checks the status of the report.
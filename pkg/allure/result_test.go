@@ -336,6 +336,32 @@ func TestResult_Print(t *testing.T) {
 	require.Equal(t, result.Start, emptyResult.Start)
 }
 
+func TestResult_Print_historyIDWithParameters(t *testing.T) {
+	sink := NewMemorySink()
+
+	first := NewResult(testName, testFullName).WithSink(sink).WithIDStrategy(ParametersIDStrategy{})
+	first.Parameters = append(NewParameters("case", "1"), NewParameter("run", 1).Excluded())
+	require.NoError(t, first.Print())
+
+	second := NewResult(testName, testFullName).WithSink(sink).WithIDStrategy(ParametersIDStrategy{})
+	second.Parameters = append(NewParameters("case", "1"), NewParameter("run", 2).Excluded())
+	require.NoError(t, second.Print())
+
+	third := NewResult(testName, testFullName).WithSink(sink).WithIDStrategy(ParametersIDStrategy{})
+	third.Parameters = NewParameters("case", "2")
+	require.NoError(t, third.Print())
+
+	require.NotEqual(t, getMD5Hash(first.TestCaseID), first.HistoryID)
+	require.Equal(t, first.HistoryID, second.HistoryID)
+	require.NotEqual(t, first.HistoryID, third.HistoryID)
+
+	custom := NewResult(testName, testFullName).WithSink(sink).WithIDStrategy(ParametersIDStrategy{})
+	custom.HistoryID = "custom"
+	custom.Parameters = NewParameters("case", "1")
+	require.NoError(t, custom.Print())
+	require.Equal(t, "custom", custom.HistoryID)
+}
+
 func TestResult_Print_withAttachment(t *testing.T) {
 	attachmentText := `THIS IS A TEXT ATTACHMENT`
 	result := NewResult(testName, testFullName)
@@ -461,3 +487,69 @@ func TestResult_Done(t *testing.T) {
 	require.NoError(t, readErr)
 	require.Equal(t, attachmentText, string(bytes))
 }
+
+func TestResult_Retry(t *testing.T) {
+	sink := NewMemorySink()
+	result := NewResult(testName, testFullName).WithSink(sink)
+	result.Description = "description"
+	result.Parameters = NewParameters("case", "1")
+	result.AddLabel(NewLabel(Owner, "team"))
+	result.Links = append(result.Links, NewLink("issue", ISSUE, "https://issues/1"))
+	result.Status = Failed
+	result.Steps = append(result.Steps, NewSimpleStep("step"))
+	require.NoError(t, result.Print())
+
+	retry := result.Retry()
+	require.NotEqual(t, result.UUID, retry.UUID)
+	require.Equal(t, result.Name, retry.Name)
+	require.Equal(t, result.Description, retry.Description)
+	require.Empty(t, retry.Status)
+	require.Empty(t, retry.Steps)
+	require.NotZero(t, retry.Start)
+
+	retry.ReplaceNewLabel(Owner, "other")
+	owner, ok := result.GetFirstLabel(Owner)
+	require.True(t, ok)
+	require.Equal(t, "team", owner.GetValue())
+
+	require.NoError(t, retry.Print())
+	require.Equal(t, result.TestCaseID, retry.TestCaseID)
+	require.Equal(t, result.HistoryID, retry.HistoryID)
+
+	_, ok = sink.File(retry.UUID.String() + resultFileSuffix)
+	require.True(t, ok)
+}
+
+func TestResult_Snapshot(t *testing.T) {
+	result := NewResult(testName, testFullName)
+	result.Status = Failed
+	result.Parameters = NewParameters("case", "1")
+	result.AddLabel(NewLabel(Owner, "team"))
+	result.Attachments = append(result.Attachments, NewAttachment("log", Text, []byte("log")))
+
+	step := NewSimpleStep("step")
+	NewSimpleStep("nested").WithParent(step)
+	result.Steps = append(result.Steps, step)
+
+	snapshot := result.Snapshot()
+	require.Equal(t, result.UUID, snapshot.UUID)
+	require.Equal(t, Failed, snapshot.Status)
+	require.Len(t, snapshot.Steps, 1)
+	require.Len(t, snapshot.Steps[0].Steps, 1)
+	require.Equal(t, snapshot.Steps[0], snapshot.Steps[0].Steps[0].GetParent())
+
+	// the changes of the result don't affect the snapshot
+	result.Status = Broken
+	result.Steps = append(result.Steps, NewSimpleStep("late"))
+	step.Steps[0].Name = "renamed"
+	result.Labels[len(result.Labels)-1].Value = "other"
+	result.Attachments[0].Name = "renamed"
+
+	require.Equal(t, Failed, snapshot.Status)
+	require.Len(t, snapshot.Steps, 1)
+	require.Equal(t, "nested", snapshot.Steps[0].Steps[0].Name)
+	owner, ok := snapshot.GetFirstLabel(Owner)
+	require.True(t, ok)
+	require.Equal(t, "team", owner.GetValue())
+	require.Equal(t, "log", snapshot.Attachments[0].Name)
+}
@@ -0,0 +1,71 @@
+package results
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// FileError is an error of the file of the results directory
+type FileError struct {
+	Name string // Name of the file
+	Err  error
+}
+
+func (e *FileError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Name, e.Err)
+}
+
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// Error is returned by Read if some files of the directory are malformed
+type Error struct {
+	Errs []error
+}
+
+func (e *Error) Error() string {
+	b := make([]string, 0, len(e.Errs))
+
+	for _, err := range e.Errs {
+		b = append(b, err.Error())
+	}
+
+	return strings.Join(b, "\n")
+}
+
+func (e *Error) Unwrap() []error {
+	return e.Errs
+}
+
+var errNoUUID = errors.New("result has no uuid")
+
+func errDuplicateUUID(id uuid.UUID) error {
+	return errors.Errorf("duplicate result uuid %s", id)
+}
+
+// readFile unmarshals the file to v. A panic of the decoder on malformed content is returned as an error too.
+func readFile(path string, v interface{}) (err error) {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("malformed content: %v", r)
+		}
+	}()
+
+	if err = json.Unmarshal(content, v); err != nil {
+		return errors.Wrap(err, "malformed content")
+	}
+
+	return nil
+}
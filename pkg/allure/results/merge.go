@@ -0,0 +1,487 @@
+package results
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/goccy/go-json"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/ozontech/allure-go/pkg/allure"
+)
+
+// Launch files names
+const (
+	EnvironmentFileName = "environment.properties"
+	ExecutorFileName    = "executor.json"
+	CategoriesFileName  = "categories.json"
+)
+
+// RetryMode defines what Merge does with the results of the same test (with the same history ID)
+type RetryMode int
+
+// RetryMode constants
+const (
+	KeepRetries     RetryMode = iota // All results are kept, the Allure report shows them as retries
+	CollapseRetries                  // Only the latest result (by its stop time) of the test is kept
+)
+
+// MergeOptions are the options of Merge
+type MergeOptions struct {
+	Retries RetryMode // What to do with the results of the same test
+	Clean   bool      // Remove the output directory before merging, otherwise it must be empty
+}
+
+// MergeReport describes the merged directory
+type MergeReport struct {
+	Results     int // Number of written results
+	Containers  int // Number of written containers
+	Attachments int // Number of written attachments
+	Duplicates  int // Number of skipped results and containers with UUID already merged from other directory
+	Collapsed   int // Number of results dropped by CollapseRetries
+	Renamed     int // Number of attachments renamed because of the name collision
+}
+
+// Merge combines the results directories into the output directory:
+//   - results and containers with the same UUID (e.g. the same directory passed twice) are written once;
+//   - retries of the same test are kept or collapsed to the latest one, see RetryMode;
+//   - attachments with the same name but different content get new names, and the sources referring to them are rewritten;
+//   - `environment.properties`, `executor.json` and `categories.json` are merged in the order of the directories,
+//     so the entries of the later directory replace the ones of the earlier directory.
+//
+// Malformed files of the directories are skipped and reported with an *Error after everything else is merged.
+func Merge(output string, dirs []string, opts MergeOptions) (*MergeReport, error) {
+	if err := prepareOutput(output, opts.Clean); err != nil {
+		return nil, err
+	}
+
+	var (
+		launches = make([]*Launch, 0, len(dirs))
+		readErrs []error
+	)
+
+	for _, dir := range dirs {
+		launch, err := Read(dir)
+		if launch == nil {
+			return nil, errors.Wrapf(err, "Cannot read %s", dir)
+		}
+
+		var readErr *Error
+		if errors.As(err, &readErr) {
+			for _, err := range readErr.Errs {
+				readErrs = append(readErrs, errors.Wrap(err, dir))
+			}
+		}
+
+		launches = append(launches, launch)
+	}
+
+	m := &merger{
+		sink:        allure.NewPlainFileSink(output),
+		report:      new(MergeReport),
+		results:     make(map[uuid.UUID]bool),
+		containers:  make(map[uuid.UUID]bool),
+		attachments: make(map[string]string),
+		dropped:     collapsed(launches, opts.Retries),
+	}
+
+	for _, launch := range launches {
+		if err := m.mergeLaunch(launch); err != nil {
+			return m.report, err
+		}
+	}
+
+	for _, dir := range dirs {
+		if err := m.mergeLaunchFiles(dir); err != nil {
+			return m.report, err
+		}
+	}
+
+	if len(readErrs) > 0 {
+		return m.report, &Error{Errs: readErrs}
+	}
+
+	return m.report, nil
+}
+
+func prepareOutput(output string, clean bool) error {
+	if clean {
+		if err := os.RemoveAll(output); err != nil {
+			return errors.Wrapf(err, "Cannot clean %s", output)
+		}
+	}
+
+	entries, err := os.ReadDir(output)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if len(entries) > 0 {
+		return errors.Errorf("Output directory %s is not empty", output)
+	}
+
+	return nil
+}
+
+// collapsed returns UUIDs of the results dropped by the retry mode
+func collapsed(launches []*Launch, mode RetryMode) map[uuid.UUID]bool {
+	dropped := make(map[uuid.UUID]bool)
+	if mode != CollapseRetries {
+		return dropped
+	}
+
+	latest := make(map[string]*allure.Result)
+
+	for _, launch := range launches {
+		for _, result := range launch.Results {
+			if result.HistoryID == "" {
+				continue
+			}
+
+			current, ok := latest[result.HistoryID]
+			switch {
+			case !ok:
+				latest[result.HistoryID] = result
+			case current.UUID == result.UUID:
+				// the same result from the directory passed twice
+			case result.Stop >= current.Stop:
+				dropped[current.UUID] = true
+				latest[result.HistoryID] = result
+			default:
+				dropped[result.UUID] = true
+			}
+		}
+	}
+
+	return dropped
+}
+
+type merger struct {
+	sink   allure.Sink
+	report *MergeReport
+
+	results     map[uuid.UUID]bool
+	containers  map[uuid.UUID]bool
+	attachments map[string]string // source in the output => path of the file it was copied from
+	dropped     map[uuid.UUID]bool
+}
+
+func (m *merger) mergeLaunch(launch *Launch) error {
+	var results []*allure.Result
+
+	for _, result := range launch.Results {
+		switch {
+		case m.results[result.UUID]:
+			m.report.Duplicates++
+		case m.dropped[result.UUID]:
+			m.results[result.UUID] = true
+			m.report.Collapsed++
+		default:
+			m.results[result.UUID] = true
+			results = append(results, result)
+		}
+	}
+
+	var containers []*allure.Container
+
+	for _, container := range launch.Containers {
+		if m.containers[container.UUID] {
+			m.report.Duplicates++
+			continue
+		}
+
+		m.containers[container.UUID] = true
+
+		if m.dropChildren(container) {
+			containers = append(containers, container)
+		}
+	}
+
+	renames, err := m.copyAttachments(launch, results, containers)
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		renameSources(renames, result.Attachments)
+		walkSteps(result.Steps, func(step *allure.Step) { renameSources(renames, step.Attachments) })
+
+		if err = m.write(result.UUID.String()+ResultFileSuffix, result); err != nil {
+			return err
+		}
+		m.report.Results++
+	}
+
+	for _, container := range containers {
+		walkSteps(container.Befores, func(step *allure.Step) { renameSources(renames, step.Attachments) })
+		walkSteps(container.Afters, func(step *allure.Step) { renameSources(renames, step.Attachments) })
+
+		if err = m.write(container.UUID.String()+ContainerFileSuffix, container); err != nil {
+			return err
+		}
+		m.report.Containers++
+	}
+
+	return nil
+}
+
+// dropChildren removes the collapsed results from the container's children.
+// Returns false if the container has no children left.
+func (m *merger) dropChildren(container *allure.Container) bool {
+	if len(container.Children) == 0 {
+		return true
+	}
+
+	children := container.Children[:0]
+	for _, child := range container.Children {
+		if !m.dropped[child] {
+			children = append(children, child)
+		}
+	}
+	container.Children = children
+
+	return len(children) > 0
+}
+
+// copyAttachments copies the attachments of the results and containers to the output.
+// Returns the new sources of the attachments renamed because of the name collision.
+func (m *merger) copyAttachments(launch *Launch, results []*allure.Result, containers []*allure.Container) (map[string]string, error) {
+	var (
+		sources []string
+		seen    = make(map[string]bool)
+	)
+
+	add := func(attachments []*allure.Attachment) {
+		for _, attachment := range attachments {
+			if attachment != nil && !seen[attachment.Source] {
+				seen[attachment.Source] = true
+				sources = append(sources, attachment.Source)
+			}
+		}
+	}
+
+	for _, result := range results {
+		add(result.Attachments)
+		walkSteps(result.Steps, func(step *allure.Step) { add(step.Attachments) })
+	}
+
+	for _, container := range containers {
+		walkSteps(container.Befores, func(step *allure.Step) { add(step.Attachments) })
+		walkSteps(container.Afters, func(step *allure.Step) { add(step.Attachments) })
+	}
+
+	sort.Strings(sources)
+
+	renames := make(map[string]string)
+
+	for _, source := range sources {
+		attachment, ok := launch.Attachment(source)
+		if !ok {
+			// the missing file is already reported by Read
+			continue
+		}
+
+		name := source
+
+		if copiedFrom, ok := m.attachments[name]; ok {
+			same, err := sameContent(copiedFrom, attachment.Path)
+			if err != nil {
+				return nil, err
+			}
+
+			if same {
+				continue
+			}
+
+			name = uuid.New().String() + "-attachment" + filepath.Ext(source)
+			renames[source] = name
+			m.report.Renamed++
+		}
+
+		if err := m.copy(name, attachment.Path); err != nil {
+			return nil, err
+		}
+
+		m.attachments[name] = attachment.Path
+		m.report.Attachments++
+	}
+
+	return renames, nil
+}
+
+func (m *merger) copy(name, path string) error {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err = m.sink.(allure.StreamSink).CreateFileFrom(name, file); err != nil {
+		return errors.Wrapf(err, "Cannot write %s", name)
+	}
+
+	return nil
+}
+
+func (m *merger) write(name string, v interface{}) error {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return errors.Wrapf(err, "Failed marshal %s", name)
+	}
+
+	if err = m.sink.CreateFile(name, content); err != nil {
+		return errors.Wrapf(err, "Cannot write %s", name)
+	}
+
+	return nil
+}
+
+// mergeLaunchFiles merges the environment, the executor and the categories of the directory into the output
+func (m *merger) mergeLaunchFiles(dir string) error {
+	if err := m.mergeEnvironment(dir); err != nil {
+		return err
+	}
+
+	if err := m.mergeExecutor(dir); err != nil {
+		return err
+	}
+
+	return m.mergeCategories(dir)
+}
+
+func (m *merger) mergeEnvironment(dir string) error {
+	content, ok, err := readLaunchFile(dir, EnvironmentFileName)
+	if err != nil || !ok {
+		return err
+	}
+
+	env, err := allure.ParseEnvironment(content)
+	if err != nil {
+		return errors.Wrapf(err, "Cannot parse %s", filepath.Join(dir, EnvironmentFileName))
+	}
+
+	return env.WithSink(m.sink).Print()
+}
+
+func (m *merger) mergeExecutor(dir string) error {
+	content, ok, err := readLaunchFile(dir, ExecutorFileName)
+	if err != nil || !ok {
+		return err
+	}
+
+	executor := new(allure.Executor)
+	if err = json.Unmarshal(content, executor); err != nil {
+		return errors.Wrapf(err, "Cannot parse %s", filepath.Join(dir, ExecutorFileName))
+	}
+
+	return executor.WithSink(m.sink).Print()
+}
+
+func (m *merger) mergeCategories(dir string) error {
+	content, ok, err := readLaunchFile(dir, CategoriesFileName)
+	if err != nil || !ok {
+		return err
+	}
+
+	var categories []*allure.Category
+	if err = json.Unmarshal(content, &categories); err != nil {
+		return errors.Wrapf(err, "Cannot parse %s", filepath.Join(dir, CategoriesFileName))
+	}
+
+	for _, category := range categories {
+		if err = category.WithSink(m.sink).Print(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readLaunchFile returns the content of the file and true if it exists
+func readLaunchFile(dir, name string) ([]byte, bool, error) {
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return content, true, nil
+}
+
+func renameSources(renames map[string]string, attachments []*allure.Attachment) {
+	for _, attachment := range attachments {
+		if attachment == nil {
+			continue
+		}
+
+		if name, ok := renames[attachment.Source]; ok {
+			attachment.Source = name
+		}
+	}
+}
+
+// sameContent returns true if the files have the same content
+func sameContent(first, second string) (bool, error) {
+	firstInfo, err := os.Stat(first)
+	if err != nil {
+		return false, err
+	}
+
+	secondInfo, err := os.Stat(second)
+	if err != nil {
+		return false, err
+	}
+
+	if os.SameFile(firstInfo, secondInfo) {
+		return true, nil
+	}
+
+	if firstInfo.Size() != secondInfo.Size() {
+		return false, nil
+	}
+
+	firstFile, err := os.Open(filepath.Clean(first))
+	if err != nil {
+		return false, err
+	}
+	defer firstFile.Close()
+
+	secondFile, err := os.Open(filepath.Clean(second))
+	if err != nil {
+		return false, err
+	}
+	defer secondFile.Close()
+
+	const chunkSize = 32 * 1024
+
+	firstChunk := make([]byte, chunkSize)
+	secondChunk := make([]byte, chunkSize)
+
+	for {
+		n, firstErr := io.ReadFull(firstFile, firstChunk)
+		_, secondErr := io.ReadFull(secondFile, secondChunk[:n])
+
+		if !bytes.Equal(firstChunk[:n], secondChunk[:n]) {
+			return false, nil
+		}
+
+		if firstErr == io.EOF || firstErr == io.ErrUnexpectedEOF {
+			return true, nil
+		}
+
+		if firstErr != nil {
+			return false, firstErr
+		}
+
+		if secondErr != nil {
+			return false, secondErr
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package results
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ozontech/allure-go/pkg/allure"
+)
+
+func writeResult(t *testing.T, dir, fullName string, status allure.Status, stop int64, attachments ...*allure.Attachment) *allure.Result {
+	sink := allure.NewFileSink(dir)
+
+	result := allure.NewResult(fullName, fullName).WithSink(sink)
+	result.Status = status
+	result.Attachments = attachments
+	require.NoError(t, result.Print())
+
+	// rewrite the result with the fixed stop time
+	result.Stop = stop
+	content, err := json.Marshal(result)
+	require.NoError(t, err)
+	require.NoError(t, sink.CreateFile(result.UUID.String()+ResultFileSuffix, content))
+
+	return result
+}
+
+func TestMerge(t *testing.T) {
+	var (
+		first  = t.TempDir()
+		second = t.TempDir()
+		output = filepath.Join(t.TempDir(), "merged")
+	)
+
+	collision := allure.NewAttachment("log", allure.Text, []byte("first"))
+	failed := writeResult(t, first, "pkg/TestRetried", allure.Failed, 100, collision)
+	writeResult(t, first, "pkg/TestOther", allure.Passed, 100)
+
+	container := allure.NewContainer().WithSink(allure.NewFileSink(first))
+	container.AddChild(failed.UUID)
+	container.Befores = append(container.Befores, allure.NewSimpleStep("before"))
+	require.NoError(t, container.Print())
+
+	sameName := allure.NewAttachment("log", allure.Text, []byte("second"))
+	sameName.Source = collision.Source
+	passed := writeResult(t, second, "pkg/TestRetried", allure.Passed, 200, sameName)
+
+	require.NoError(t, allure.NewEnvironment().With("os", "linux").With("shard", "1").WithSink(allure.NewFileSink(first)).Print())
+	require.NoError(t, allure.NewEnvironment().With("shard", "2").WithSink(allure.NewFileSink(second)).Print())
+	require.NoError(t, allure.NewCategory("Timeouts").WithStatuses(allure.Broken).WithSink(allure.NewFileSink(first)).Print())
+	require.NoError(t, allure.NewCategory("Timeouts").WithStatuses(allure.Failed).WithSink(allure.NewFileSink(second)).Print())
+
+	t.Run("keep", func(t *testing.T) {
+		// the directory passed twice is merged once, but its launch files are merged last
+		report, err := Merge(output, []string{first, second, first}, MergeOptions{Clean: true})
+		require.NoError(t, err)
+		require.Equal(t, &MergeReport{Results: 3, Containers: 1, Attachments: 2, Duplicates: 3, Renamed: 1}, report)
+
+		launch, err := Read(output)
+		require.NoError(t, err)
+		require.Len(t, launch.ResultsByHistoryID(failed.HistoryID), 2)
+
+		merged, ok := launch.Result(passed.UUID)
+		require.True(t, ok)
+		require.NotEqual(t, collision.Source, merged.Attachments[0].Source)
+
+		content, err := os.ReadFile(launch.Attachments(merged)[0].Path)
+		require.NoError(t, err)
+		require.Equal(t, "second", string(content))
+
+		content, err = os.ReadFile(filepath.Join(output, EnvironmentFileName))
+		require.NoError(t, err)
+		require.Equal(t, "os=linux\nshard=1\n", string(content))
+
+		content, err = os.ReadFile(filepath.Join(output, CategoriesFileName))
+		require.NoError(t, err)
+
+		var categories []*allure.Category
+		require.NoError(t, json.Unmarshal(content, &categories))
+		require.Len(t, categories, 1)
+		require.Equal(t, []allure.Status{allure.Broken}, categories[0].MatchedStatuses)
+	})
+
+	t.Run("not empty", func(t *testing.T) {
+		_, err := Merge(output, []string{first}, MergeOptions{})
+		require.Error(t, err)
+	})
+
+	t.Run("collapse", func(t *testing.T) {
+		report, err := Merge(output, []string{first, second}, MergeOptions{Retries: CollapseRetries, Clean: true})
+		require.NoError(t, err)
+		require.Equal(t, &MergeReport{Results: 2, Attachments: 1, Collapsed: 1}, report)
+
+		launch, err := Read(output)
+		require.NoError(t, err)
+		require.Len(t, launch.ResultsByHistoryID(failed.HistoryID), 1)
+		require.Equal(t, passed.UUID, launch.ResultsByHistoryID(failed.HistoryID)[0].UUID)
+		require.Empty(t, launch.Containers)
+	})
+}
+
+func TestMerge_Malformed(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(t.TempDir(), "merged")
+
+	writeResult(t, dir, "pkg/Test", allure.Passed, 100)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken-result.json"), []byte("{"), 0o644))
+
+	report, err := Merge(output, []string{dir}, MergeOptions{})
+	require.Error(t, err)
+	require.Equal(t, 1, report.Results)
+
+	_, err = Merge(output, []string{filepath.Join(dir, "not-exist")}, MergeOptions{Clean: true})
+	require.Error(t, err)
+}
@@ -0,0 +1,266 @@
+// Package results reads an allure-results directory back into the typed model of `pkg/allure`,
+// so the results can be post-processed (merged, exported, analyzed) without ad-hoc parsing.
+package results
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/ozontech/allure-go/pkg/allure"
+)
+
+// Output files suffixes
+const (
+	ResultFileSuffix    = "-result.json"
+	ContainerFileSuffix = "-container.json"
+)
+
+// Attachment is an attachment of the result or the container resolved to the file in the results directory
+type Attachment struct {
+	*allure.Attachment
+
+	Path string // Path to the attachment file
+}
+
+// Launch is an indexed model of the allure-results directory
+type Launch struct {
+	Dir        string              // Path to the results directory
+	Results    []*allure.Result    // All results sorted by the file name
+	Containers []*allure.Container // All containers sorted by the file name
+
+	resultsByUUID      map[uuid.UUID]*allure.Result
+	resultsByHistoryID map[string][]*allure.Result
+	containersByChild  map[uuid.UUID][]*allure.Container
+	attachments        map[string]*Attachment
+}
+
+// Read loads all results, containers and attachments of the directory.
+// Malformed files and attachments without a file are reported with an *Error, while the rest of the directory is still loaded,
+// so the returned Launch is never nil unless the directory can't be read at all.
+func Read(dir string) (*Launch, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		launch = newLaunch(dir)
+		errs   []error
+	)
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		switch {
+		case strings.HasSuffix(name, ResultFileSuffix):
+			result := new(allure.Result)
+			if err = readFile(filepath.Join(dir, name), result); err != nil {
+				errs = append(errs, &FileError{Name: name, Err: err})
+				continue
+			}
+
+			if err = launch.addResult(result); err != nil {
+				errs = append(errs, &FileError{Name: name, Err: err})
+			}
+		case strings.HasSuffix(name, ContainerFileSuffix):
+			container := new(allure.Container)
+			if err = readFile(filepath.Join(dir, name), container); err != nil {
+				errs = append(errs, &FileError{Name: name, Err: err})
+				continue
+			}
+
+			launch.addContainer(container)
+		}
+	}
+
+	errs = append(errs, launch.resolveAttachments()...)
+
+	if len(errs) > 0 {
+		return launch, &Error{Errs: errs}
+	}
+
+	return launch, nil
+}
+
+func newLaunch(dir string) *Launch {
+	return &Launch{
+		Dir:                dir,
+		resultsByUUID:      make(map[uuid.UUID]*allure.Result),
+		resultsByHistoryID: make(map[string][]*allure.Result),
+		containersByChild:  make(map[uuid.UUID][]*allure.Container),
+		attachments:        make(map[string]*Attachment),
+	}
+}
+
+func (l *Launch) addResult(result *allure.Result) error {
+	if result.UUID == uuid.Nil {
+		return errNoUUID
+	}
+
+	if _, ok := l.resultsByUUID[result.UUID]; ok {
+		return errDuplicateUUID(result.UUID)
+	}
+
+	l.Results = append(l.Results, result)
+	l.resultsByUUID[result.UUID] = result
+
+	if result.HistoryID != "" {
+		l.resultsByHistoryID[result.HistoryID] = append(l.resultsByHistoryID[result.HistoryID], result)
+	}
+
+	return nil
+}
+
+func (l *Launch) addContainer(container *allure.Container) {
+	l.Containers = append(l.Containers, container)
+
+	for _, child := range container.Children {
+		l.containersByChild[child] = append(l.containersByChild[child], container)
+	}
+}
+
+// resolveAttachments resolves the attachments of all results and containers to their files
+func (l *Launch) resolveAttachments() []error {
+	var errs []error
+
+	resolve := func(attachments []*allure.Attachment) {
+		for _, attachment := range attachments {
+			if attachment == nil || attachment.Source == "" {
+				continue
+			}
+
+			if _, ok := l.attachments[attachment.Source]; ok {
+				continue
+			}
+
+			path := filepath.Join(l.Dir, attachment.Source)
+			if _, err := os.Stat(path); err != nil {
+				errs = append(errs, &FileError{Name: attachment.Source, Err: err})
+				continue
+			}
+
+			l.attachments[attachment.Source] = &Attachment{Attachment: attachment, Path: path}
+		}
+	}
+
+	for _, result := range l.Results {
+		resolve(result.Attachments)
+		walkSteps(result.Steps, func(step *allure.Step) { resolve(step.Attachments) })
+	}
+
+	for _, container := range l.Containers {
+		walkSteps(container.Befores, func(step *allure.Step) { resolve(step.Attachments) })
+		walkSteps(container.Afters, func(step *allure.Step) { resolve(step.Attachments) })
+	}
+
+	return errs
+}
+
+// Result returns the result with passed UUID and true if it exists
+func (l *Launch) Result(id uuid.UUID) (*allure.Result, bool) {
+	result, ok := l.resultsByUUID[id]
+	return result, ok
+}
+
+// ResultsByHistoryID returns all results with passed history ID (e.g. the retries of the same test) in the file name order
+func (l *Launch) ResultsByHistoryID(historyID string) []*allure.Result {
+	return l.resultsByHistoryID[historyID]
+}
+
+// HistoryIDs returns the sorted unique history IDs of all results
+func (l *Launch) HistoryIDs() []string {
+	ids := make([]string, 0, len(l.resultsByHistoryID))
+	for id := range l.resultsByHistoryID {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+// ContainersOf returns all containers (e.g. with the setup and teardown steps) the result with passed UUID is a child of
+func (l *Launch) ContainersOf(id uuid.UUID) []*allure.Container {
+	return l.containersByChild[id]
+}
+
+// Children returns the results of the container's children. Children without a result are skipped.
+func (l *Launch) Children(container *allure.Container) []*allure.Result {
+	var children []*allure.Result
+
+	for _, child := range container.Children {
+		if result, ok := l.resultsByUUID[child]; ok {
+			children = append(children, result)
+		}
+	}
+
+	return children
+}
+
+// Attachment returns the attachment with passed source and true if its file exists
+func (l *Launch) Attachment(source string) (*Attachment, bool) {
+	attachment, ok := l.attachments[source]
+	return attachment, ok
+}
+
+// Attachments returns the resolved attachments of the result, of all its steps and of the steps of its containers
+func (l *Launch) Attachments(result *allure.Result) []*Attachment {
+	var attachments []*Attachment
+
+	collect := func(list []*allure.Attachment) {
+		for _, attachment := range list {
+			if attachment == nil {
+				continue
+			}
+
+			if resolved, ok := l.attachments[attachment.Source]; ok {
+				attachments = append(attachments, resolved)
+			}
+		}
+	}
+
+	for _, container := range l.ContainersOf(result.UUID) {
+		walkSteps(container.Befores, func(step *allure.Step) { collect(step.Attachments) })
+	}
+
+	collect(result.Attachments)
+	walkSteps(result.Steps, func(step *allure.Step) { collect(step.Attachments) })
+
+	for _, container := range l.ContainersOf(result.UUID) {
+		walkSteps(container.Afters, func(step *allure.Step) { collect(step.Attachments) })
+	}
+
+	return attachments
+}
+
+// AllAttachments returns all resolved attachments sorted by their source
+func (l *Launch) AllAttachments() []*Attachment {
+	attachments := make([]*Attachment, 0, len(l.attachments))
+	for _, attachment := range l.attachments {
+		attachments = append(attachments, attachment)
+	}
+
+	sort.Slice(attachments, func(i, j int) bool {
+		return attachments[i].Source < attachments[j].Source
+	})
+
+	return attachments
+}
+
+// walkSteps calls fn for every step of the tree in the depth-first order
+func walkSteps(steps []*allure.Step, fn func(step *allure.Step)) {
+	for _, step := range steps {
+		if step == nil {
+			continue
+		}
+
+		fn(step)
+		walkSteps(step.Steps, fn)
+	}
+}
@@ -0,0 +1,118 @@
+package results
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ozontech/allure-go/pkg/allure"
+)
+
+func writeLaunch(t *testing.T, dir string) (*allure.Result, *allure.Result, *allure.Container) {
+	sink := allure.NewFileSink(dir)
+	allure.SetSink(sink)
+	defer allure.SetSink(nil)
+
+	first := allure.NewResult("first", "pkg/first").WithSink(sink)
+	first.Attachments = append(first.Attachments, allure.NewAttachment("log", allure.Text, []byte("log")))
+	step := allure.NewSimpleStep("step")
+	step.Attachments = append(step.Attachments, allure.NewAttachment("json", allure.JSON, []byte("{}")))
+	first.Steps = append(first.Steps, step)
+	require.NoError(t, first.Done())
+
+	retry := allure.NewResult("first", "pkg/first").WithSink(sink)
+	require.NoError(t, retry.Done())
+
+	container := allure.NewContainer().WithSink(sink)
+	container.AddChild(first.UUID)
+	container.AddChild(retry.UUID)
+	before := allure.NewSimpleStep("before")
+	before.Attachments = append(before.Attachments, allure.NewAttachment("before", allure.Text, []byte("before")))
+	container.Befores = append(container.Befores, before)
+	require.NoError(t, container.Done())
+
+	return first, retry, container
+}
+
+func TestRead(t *testing.T) {
+	dir := t.TempDir()
+	first, retry, container := writeLaunch(t, dir)
+
+	// launch files and temporary files are not results
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "environment.properties"), []byte("os=linux"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".tmp-result.json.123.tmp"), []byte("{"), 0o644))
+
+	launch, err := Read(dir)
+	require.NoError(t, err)
+	require.Equal(t, dir, launch.Dir)
+	require.Len(t, launch.Results, 2)
+	require.Len(t, launch.Containers, 1)
+
+	result, ok := launch.Result(first.UUID)
+	require.True(t, ok)
+	require.Equal(t, "first", result.Name)
+	require.Equal(t, allure.Passed, result.Status)
+
+	require.Len(t, launch.ResultsByHistoryID(first.HistoryID), 2)
+	require.Equal(t, []string{first.HistoryID}, launch.HistoryIDs())
+
+	require.Len(t, launch.ContainersOf(retry.UUID), 1)
+	require.Equal(t, container.UUID, launch.ContainersOf(retry.UUID)[0].UUID)
+	require.Len(t, launch.Children(launch.Containers[0]), 2)
+
+	attachments := launch.Attachments(result)
+	require.Len(t, attachments, 3)
+	require.Equal(t, "before", attachments[0].Name)
+	require.Equal(t, "log", attachments[1].Name)
+	require.Equal(t, "json", attachments[2].Name)
+
+	content, err := os.ReadFile(attachments[2].Path)
+	require.NoError(t, err)
+	require.Equal(t, "{}", string(content))
+
+	require.Len(t, launch.AllAttachments(), 3)
+
+	attachment, ok := launch.Attachment(first.Attachments[0].Source)
+	require.True(t, ok)
+	require.Equal(t, filepath.Join(dir, first.Attachments[0].Source), attachment.Path)
+}
+
+func TestRead_Malformed(t *testing.T) {
+	dir := t.TempDir()
+	first, _, _ := writeLaunch(t, dir)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken-result.json"), []byte(`{"uuid": `), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "empty-result.json"), []byte(`{}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken-container.json"), []byte(`[]`), 0o644))
+	require.NoError(t, os.Remove(filepath.Join(dir, first.Attachments[0].Source)))
+
+	launch, err := Read(dir)
+	require.Error(t, err)
+	require.NotNil(t, launch)
+	require.Len(t, launch.Results, 2)
+	require.Len(t, launch.Containers, 1)
+
+	var readErr *Error
+	require.True(t, errors.As(err, &readErr))
+	require.Len(t, readErr.Errs, 4)
+
+	var names []string
+	for _, err := range readErr.Errs {
+		var fileErr *FileError
+		require.True(t, errors.As(err, &fileErr))
+		names = append(names, fileErr.Name)
+	}
+	require.ElementsMatch(t, []string{"broken-result.json", "empty-result.json", "broken-container.json", first.Attachments[0].Source}, names)
+
+	_, ok := launch.Attachment(first.Attachments[0].Source)
+	require.False(t, ok)
+}
+
+func TestRead_NotExist(t *testing.T) {
+	launch, err := Read(filepath.Join(t.TempDir(), "not-exist"))
+	require.Error(t, err)
+	require.Nil(t, launch)
+}
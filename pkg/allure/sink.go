@@ -0,0 +1,413 @@
+package allure
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+)
+
+// Sink is a destination of everything allure-go prints: results, containers and attachments.
+// Every entity is passed to the Sink as a named file (e.g. `<uuid>-result.json`),
+// so any FileManager can be used as a Sink too.
+type Sink interface {
+	CreateFile(name string, content []byte) error
+}
+
+// StreamSink is a Sink that can write a file from io.Reader without loading the whole content into memory
+type StreamSink interface {
+	Sink
+	CreateFileFrom(name string, content io.Reader) error
+}
+
+// createFileFrom writes the file from the reader to the sink.
+// If the sink is not a StreamSink, the content is read into memory first.
+func createFileFrom(sink Sink, name string, content io.Reader) error {
+	if streamSink, ok := sink.(StreamSink); ok {
+		return streamSink.CreateFileFrom(name, content)
+	}
+
+	bContent, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+
+	return sink.CreateFile(name, bContent)
+}
+
+// dirSink is a Sink that writes files to the directory on the filesystem
+type dirSink interface {
+	outputDir() (string, error)
+}
+
+// mergeFunc returns the new content of the file merged with its existing content.
+// existing is nil if the file does not exist yet.
+type mergeFunc func(existing []byte) ([]byte, error)
+
+// mergeSink is a Sink that can merge the file with its existing content atomically
+type mergeSink interface {
+	mergeFile(name string, merge mergeFunc) error
+}
+
+// mergeFile merges the file of the sink with the new content.
+// If the sink can't read its files, the file is overwritten with the content merged with nothing.
+func mergeFile(sink Sink, name string, merge mergeFunc) error {
+	if ms, ok := sink.(mergeSink); ok {
+		return ms.mergeFile(name, merge)
+	}
+
+	content, err := merge(nil)
+	if err != nil {
+		return err
+	}
+
+	return sink.CreateFile(name, content)
+}
+
+var (
+	sinkMu      sync.RWMutex
+	defaultSink Sink = &fileSink{}
+	currentSink      = defaultSink
+)
+
+// SetSink sets the process-wide Sink. It is used by every Result, Container and Attachment
+// that has no Sink of its own. Passing nil restores the default filesystem Sink
+// (`$ALLURE_OUTPUT_PATH`/`$ALLURE_OUTPUT_FOLDER`).
+func SetSink(sink Sink) {
+	sinkMu.Lock()
+	defer sinkMu.Unlock()
+
+	if sink == nil {
+		sink = defaultSink
+	}
+
+	currentSink = sink
+}
+
+// GetSink returns the process-wide Sink
+func GetSink() Sink {
+	sinkMu.RLock()
+	defer sinkMu.RUnlock()
+
+	return currentSink
+}
+
+func sinkOrDefault(sink Sink) Sink {
+	if sink != nil {
+		return sink
+	}
+
+	return GetSink()
+}
+
+// fileSink writes files to the results directory on the filesystem.
+// If dir is empty, the directory is resolved from `$ALLURE_OUTPUT_PATH`/`$ALLURE_OUTPUT_FOLDER` on each write.
+type fileSink struct {
+	dir   string
+	plain bool // If true - the CleanupPolicy is not applied to the directory
+
+	mu      sync.Mutex
+	created map[string]bool
+}
+
+// NewFileSink returns a Sink that writes files to the passed directory.
+// The directory is created and the CleanupPolicy is applied to it on the first write.
+func NewFileSink(dir string) Sink {
+	return &fileSink{dir: dir}
+}
+
+// NewPlainFileSink returns a Sink that writes files to the passed directory without applying the CleanupPolicy,
+// so the tools post-processing the results of the launch (e.g. `allure-go convert`) never wipe or rotate them.
+// The directory is created on the first write.
+func NewPlainFileSink(dir string) Sink {
+	return &fileSink{dir: dir, plain: true}
+}
+
+// CreateFile creates the file with passed name and content in the results directory
+func (s *fileSink) CreateFile(name string, content []byte) error {
+	// every attempt reads the content from the start
+	return s.writeFile(name, true, func(path string) error {
+		return writeFromAtomic(path, bytes.NewReader(content))
+	})
+}
+
+// CreateFileFrom creates the file with passed name in the results directory and copies the content to it.
+// The write is retried only if the reader can be rewound (see io.Seeker).
+func (s *fileSink) CreateFileFrom(name string, content io.Reader) error {
+	var (
+		seeker, canRetry = content.(io.Seeker)
+		start            int64
+		attempted        bool
+	)
+
+	if canRetry {
+		var err error
+		if start, err = seeker.Seek(0, io.SeekCurrent); err != nil {
+			canRetry = false
+		}
+	}
+
+	return s.writeFile(name, canRetry, func(path string) error {
+		if attempted {
+			if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+				return errors.Wrapf(err, "Cannot rewind the content of %s", name)
+			}
+		}
+
+		attempted = true
+
+		return writeFromAtomic(path, content)
+	})
+}
+
+// writeFile writes the file to the results directory with write,
+// the write is retried once if the directory has been removed since it was created
+func (s *fileSink) writeFile(name string, canRetry bool, write func(path string) error) error {
+	dir, err := s.outputDir()
+	if err != nil {
+		return err
+	}
+
+	err = write(filepath.Join(dir, name))
+	if os.IsNotExist(err) && canRetry {
+		// the directory has been removed since it was created
+		s.forget(dir)
+
+		if dir, err = s.outputDir(); err != nil {
+			return err
+		}
+
+		err = write(filepath.Join(dir, name))
+	}
+
+	return err
+}
+
+// mergeFile merges the file under the lock file, so the processes writing to the same directory don't lose each other's content
+func (s *fileSink) mergeFile(name string, merge mergeFunc) error {
+	dir, err := s.outputDir()
+	if err != nil {
+		return err
+	}
+
+	unlock, err := lockFile(filepath.Join(dir, "."+name+".lock"))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	path := filepath.Join(dir, name)
+
+	existing, err := os.ReadFile(filepath.Clean(path))
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "Cannot read %s", name)
+	}
+
+	content, err := merge(existing)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, content)
+}
+
+// Dir returns the results directory of the Sink
+func (s *fileSink) Dir() string {
+	if s.dir != "" {
+		return s.dir
+	}
+
+	return getResultPath()
+}
+
+// outputDir returns the results directory, creates it and applies the CleanupPolicy once
+func (s *fileSink) outputDir() (string, error) {
+	dir := s.Dir()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.created[dir] {
+		return dir, nil
+	}
+
+	if s.plain {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return "", errors.Wrap(err, "Cannot create results directory")
+		}
+	} else if err := prepareResultsDir(dir); err != nil {
+		return "", err
+	}
+
+	if s.created == nil {
+		s.created = make(map[string]bool)
+	}
+	s.created[dir] = true
+
+	return dir, nil
+}
+
+func (s *fileSink) forget(dir string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.created, dir)
+}
+
+// MemorySink is a Sink that keeps all files in memory.
+// It is useful to capture the results in process (e.g. to check them in tests).
+type MemorySink struct {
+	mu    sync.RWMutex
+	files map[string][]byte
+}
+
+// NewMemorySink returns pointer to the new empty MemorySink
+func NewMemorySink() *MemorySink {
+	return &MemorySink{files: make(map[string][]byte)}
+}
+
+// CreateFile stores a copy of the content under passed name
+func (s *MemorySink) CreateFile(name string, content []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.files[name] = append([]byte(nil), content...)
+
+	return nil
+}
+
+func (s *MemorySink) mergeFile(name string, merge mergeFunc) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	content, err := merge(s.files[name])
+	if err != nil {
+		return err
+	}
+
+	s.files[name] = content
+
+	return nil
+}
+
+// File returns the content of the file with passed name and true if the file exists
+func (s *MemorySink) File(name string) ([]byte, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	content, ok := s.files[name]
+
+	return content, ok
+}
+
+// Files returns the sorted names of all stored files
+func (s *MemorySink) Files() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := make([]string, 0, len(s.files))
+	for name := range s.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Results unmarshals and returns all stored `*-result.json` files
+func (s *MemorySink) Results() ([]*Result, error) {
+	var results []*Result
+
+	for _, name := range s.filesWithSuffix(resultFileSuffix) {
+		content, _ := s.File(name)
+
+		result := new(Result)
+		if err := json.Unmarshal(content, result); err != nil {
+			return nil, errors.Wrapf(err, "Failed unmarshal %s", name)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// Containers unmarshals and returns all stored `*-container.json` files
+func (s *MemorySink) Containers() ([]*Container, error) {
+	var containers []*Container
+
+	for _, name := range s.filesWithSuffix(containerFileSuffix) {
+		content, _ := s.File(name)
+
+		container := new(Container)
+		if err := json.Unmarshal(content, container); err != nil {
+			return nil, errors.Wrapf(err, "Failed unmarshal %s", name)
+		}
+
+		containers = append(containers, container)
+	}
+
+	return containers, nil
+}
+
+// Reset removes all stored files
+func (s *MemorySink) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.files = make(map[string][]byte)
+}
+
+func (s *MemorySink) filesWithSuffix(suffix string) []string {
+	var names []string
+
+	for _, name := range s.Files() {
+		if strings.HasSuffix(name, suffix) {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// multiSink writes every file to all of its sinks
+type multiSink struct {
+	sinks []Sink
+}
+
+// NewMultiSink returns a Sink that fans out every file to all passed sinks.
+// All sinks are written even if some of them fail, the errors are joined.
+func NewMultiSink(sinks ...Sink) Sink {
+	return &multiSink{sinks: sinks}
+}
+
+// CreateFile creates the file in every sink
+func (s *multiSink) CreateFile(name string, content []byte) error {
+	var errs []error
+
+	for _, sink := range s.sinks {
+		if err := sink.CreateFile(name, content); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return joinErrors(errs)
+}
+
+func (s *multiSink) mergeFile(name string, merge mergeFunc) error {
+	var errs []error
+
+	for _, sink := range s.sinks {
+		if err := mergeFile(sink, name, merge); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return joinErrors(errs)
+}
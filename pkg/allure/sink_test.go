@@ -0,0 +1,111 @@
+package allure
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type sinkMock struct {
+	err   error
+	files []string
+}
+
+func (m *sinkMock) CreateFile(name string, content []byte) error {
+	m.files = append(m.files, name)
+	return m.err
+}
+
+func TestSetSink(t *testing.T) {
+	sink := NewMemorySink()
+	SetSink(sink)
+	require.Equal(t, sink, GetSink())
+
+	SetSink(nil)
+	require.Equal(t, defaultSink, GetSink())
+}
+
+func TestFileSink_CreateFile(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested", "allure-results")
+	sink := NewFileSink(dir)
+
+	require.NoError(t, sink.CreateFile("test.txt", []byte("SOME TEXT")))
+
+	content, err := os.ReadFile(filepath.Join(dir, "test.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "SOME TEXT", string(content))
+}
+
+// removingReader removes the directory on the first read, like a cleanup of the results directory running meanwhile
+type removingReader struct {
+	*strings.Reader
+	dir     string
+	removed bool
+}
+
+func (r *removingReader) Read(p []byte) (int, error) {
+	if !r.removed {
+		r.removed = true
+		_ = os.RemoveAll(r.dir)
+	}
+
+	return r.Reader.Read(p)
+}
+
+func TestFileSink_CreateFileFrom_removedDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "allure-results")
+	sink := NewFileSink(dir).(StreamSink)
+
+	require.NoError(t, sink.CreateFileFrom("test.txt", &removingReader{Reader: strings.NewReader("SOME TEXT"), dir: dir}))
+
+	content, err := os.ReadFile(filepath.Join(dir, "test.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "SOME TEXT", string(content))
+}
+
+func TestMemorySink_Result(t *testing.T) {
+	sink := NewMemorySink()
+
+	result := NewResult(testName, testFullName).WithSink(sink)
+	result.Attachments = append(result.Attachments, NewAttachment("attach", Text, []byte("text")))
+	require.NoError(t, result.Done())
+
+	container := NewContainer().WithSink(sink)
+	container.AddChild(result.UUID)
+	container.Befores = append(container.Befores, NewSimpleStep("before"))
+	require.NoError(t, container.Done())
+
+	require.Len(t, sink.Files(), 3)
+
+	content, ok := sink.File(result.Attachments[0].Source)
+	require.True(t, ok)
+	require.Equal(t, "text", string(content))
+
+	results, err := sink.Results()
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, result.UUID, results[0].UUID)
+	require.Equal(t, Passed, results[0].Status)
+
+	containers, err := sink.Containers()
+	require.NoError(t, err)
+	require.Len(t, containers, 1)
+	require.Equal(t, result.UUID, containers[0].Children[0])
+
+	sink.Reset()
+	require.Empty(t, sink.Files())
+}
+
+func TestMultiSink_CreateFile(t *testing.T) {
+	first := &sinkMock{err: errors.New("first")}
+	second := &sinkMock{}
+
+	err := NewMultiSink(first, second).CreateFile("test.txt", []byte("SOME TEXT"))
+	require.EqualError(t, err, "first")
+	require.Equal(t, []string{"test.txt"}, first.files)
+	require.Equal(t, []string{"test.txt"}, second.files)
+}
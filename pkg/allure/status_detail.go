@@ -2,6 +2,9 @@ package allure
 
 // StatusDetail ...
 type StatusDetail struct {
-	Message string `json:"message"` // Abridged version of the message
-	Trace   string `json:"trace"`   // Full message
+	Message string `json:"message"`         // Abridged version of the message
+	Trace   string `json:"trace"`           // Full message
+	Known   bool   `json:"known,omitempty"` // The failure is a known issue
+	Muted   bool   `json:"muted,omitempty"` // The failure of the test doesn't fail the run
+	Flaky   bool   `json:"flaky,omitempty"` // The test is unstable (e.g. it passed on retry)
 }
@@ -40,6 +40,39 @@ func NewSimpleStep(name string, parameters ...*Parameter) *Step {
 	return NewStep(name, Passed, GetNow(), GetNow(), parameters)
 }
 
+// copySteps returns the deep copies of the steps with the parent
+func copySteps(steps []*Step, parent *Step) []*Step {
+	if steps == nil {
+		return nil
+	}
+
+	copied := make([]*Step, 0, len(steps))
+	for _, step := range steps {
+		stepCopy := &Step{
+			Name:           step.Name,
+			Status:         step.Status,
+			StatusDetails:  step.StatusDetails,
+			Attachments:    copyAttachments(step.Attachments),
+			Start:          step.Start,
+			Stop:           step.Stop,
+			Parameters:     copyParameters(step.Parameters),
+			ExpectedResult: step.ExpectedResult,
+			parent:         parent,
+		}
+		stepCopy.Steps = copySteps(step.Steps, stepCopy)
+		stepCopy.ExpectedSteps = copySteps(step.ExpectedSteps, stepCopy)
+
+		copied = append(copied, stepCopy)
+	}
+
+	return copied
+}
+
+// MaskedValues returns the values of the masked parameters of the step and of its nested steps (see MaskKnownValues)
+func (s *Step) MaskedValues() []string {
+	return maskedValuesOf(nil, s.Parameters, s.Steps)
+}
+
 // GetParent returns step's parent
 func (s *Step) GetParent() *Step {
 	return s.parent
@@ -151,15 +184,22 @@ func (s *Step) WithChild(child *Step) *Step {
 // PrintAttachments Goes through all `allure.Attachments` of the `Step.Attachments`
 // array and calls `Print()` method on `allure.Attachment`.
 func (s *Step) PrintAttachments() {
-	for _, a := range s.Attachments {
-		_ = a.Print()
-	}
+	_ = s.printAttachments(GetSink())
+}
 
-	if s.Steps == nil {
-		return
+// printAttachments prints attachments of the step and its children to the sink and returns all occurred errors
+func (s *Step) printAttachments(sink Sink) []error {
+	var errs []error
+
+	for _, a := range s.Attachments {
+		if err := a.printTo(sink); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
 	for _, step := range s.Steps {
-		step.PrintAttachments()
+		errs = append(errs, step.printAttachments(sink)...)
 	}
+
+	return errs
 }
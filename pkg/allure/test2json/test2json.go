@@ -0,0 +1,413 @@
+// Package test2json converts the `go test -json` (test2json) events to allure results,
+// so the packages using plain `*testing.T` get into the same report as the ones using allure-go runners.
+package test2json
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	"github.com/ozontech/allure-go/pkg/allure"
+)
+
+// Event actions, see `go doc test2json`
+const (
+	ActionStart  = "start"
+	ActionRun    = "run"
+	ActionPause  = "pause"
+	ActionCont   = "cont"
+	ActionPass   = "pass"
+	ActionBench  = "bench"
+	ActionFail   = "fail"
+	ActionOutput = "output"
+	ActionSkip   = "skip"
+)
+
+// frameworkName is the value of the framework label of the converted results
+const frameworkName = "go test"
+
+// maxLineSize is the maximum size of one event line
+const maxLineSize = 16 * 1024 * 1024
+
+// Event is the event of `go test -json`
+type Event struct {
+	Time    time.Time `json:"Time"`
+	Action  string    `json:"Action"`
+	Package string    `json:"Package"`
+	Test    string    `json:"Test"`
+	Elapsed float64   `json:"Elapsed"` // seconds
+	Output  string    `json:"Output"`
+}
+
+// Summary is the number of converted results by their status
+type Summary struct {
+	Passed  int
+	Failed  int
+	Broken  int
+	Skipped int
+}
+
+// Total returns the number of converted results
+func (s Summary) Total() int {
+	return s.Passed + s.Failed + s.Broken + s.Skipped
+}
+
+// Converter converts the events to allure results:
+//   - the package becomes the `package` and `parentSuite` labels, the top-level test - the `suite` label,
+//     and the path of the parent subtests - the `subSuite` label;
+//   - `pass`, `fail` and `skip` become passed, failed and skipped statuses;
+//   - the output of the test becomes the `output` attachment and the status trace of the failed test;
+//   - the tests not finished when their package is (e.g. because of a panic or a timeout) become broken.
+//
+// A test with subtests gets its own result only if it has failed while all of its subtests have not,
+// otherwise the subtests already carry the failure.
+type Converter struct {
+	Echo io.Writer // If set, the output of the tests is copied to it
+
+	sink     allure.Sink
+	tests    map[string]*test
+	packages map[string]*pkg
+	summary  Summary
+	errs     []error
+}
+
+type pkg struct {
+	name   string
+	output bytes.Buffer
+	failed bool // any result of the package has failed
+	tests  []*test
+}
+
+type test struct {
+	pkg      *pkg
+	name     string
+	start    time.Time
+	output   bytes.Buffer
+	parent   *test
+	children int
+	failed   bool // any subtest has failed
+	done     bool
+}
+
+// NewConverter returns pointer to the new Converter that prints results to the sink.
+// If sink is nil, the process-wide Sink is used.
+func NewConverter(sink allure.Sink) *Converter {
+	return &Converter{
+		sink:     sink,
+		tests:    make(map[string]*test),
+		packages: make(map[string]*pkg),
+	}
+}
+
+// Convert reads the events from r line by line and handles them, then flushes the unfinished tests.
+// Lines that are not events (e.g. build errors printed to the same stream) are ignored.
+func (c *Converter) Convert(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+
+		event := new(Event)
+		if err := json.Unmarshal(line, event); err != nil {
+			continue
+		}
+
+		c.Handle(event)
+	}
+
+	c.Flush()
+
+	if err := scanner.Err(); err != nil {
+		c.errs = append(c.errs, errors.Wrap(err, "Cannot read events"))
+	}
+
+	return c.Err()
+}
+
+// Handle handles one event. The results are printed as soon as their tests finish.
+func (c *Converter) Handle(event *Event) {
+	if event.Package == "" {
+		return
+	}
+
+	p := c.pkg(event.Package)
+
+	if event.Test == "" {
+		c.handlePackage(p, event)
+		return
+	}
+
+	t := c.test(p, event)
+
+	switch event.Action {
+	case ActionRun:
+		t.start = event.Time
+	case ActionOutput:
+		c.echo(event.Output)
+		if !isFraming(event.Output) {
+			t.output.WriteString(event.Output)
+		}
+	case ActionPass:
+		c.finish(t, allure.Passed, event)
+	case ActionFail:
+		c.finish(t, allure.Failed, event)
+	case ActionSkip:
+		c.finish(t, allure.Skipped, event)
+	}
+}
+
+// Flush marks all unfinished tests as broken and prints them
+func (c *Converter) Flush() {
+	for _, p := range c.packages {
+		c.finishPackage(p, time.Time{})
+	}
+}
+
+// Summary returns the number of converted results by their status
+func (c *Converter) Summary() Summary {
+	return c.summary
+}
+
+// Err returns the errors of printing the results
+func (c *Converter) Err() error {
+	switch len(c.errs) {
+	case 0:
+		return nil
+	case 1:
+		return c.errs[0]
+	default:
+		messages := make([]string, 0, len(c.errs))
+		for _, err := range c.errs {
+			messages = append(messages, err.Error())
+		}
+
+		return errors.New(strings.Join(messages, "\n"))
+	}
+}
+
+func (c *Converter) pkg(name string) *pkg {
+	p, ok := c.packages[name]
+	if !ok {
+		p = &pkg{name: name}
+		c.packages[name] = p
+	}
+
+	return p
+}
+
+func (c *Converter) test(p *pkg, event *Event) *test {
+	key := p.name + "\x00" + event.Test
+
+	t, ok := c.tests[key]
+	if ok {
+		return t
+	}
+
+	t = &test{pkg: p, name: event.Test, start: event.Time}
+
+	if idx := strings.LastIndexByte(event.Test, '/'); idx > 0 {
+		if parent, ok := c.tests[p.name+"\x00"+event.Test[:idx]]; ok {
+			t.parent = parent
+			parent.children++
+		}
+	}
+
+	c.tests[key] = t
+	p.tests = append(p.tests, t)
+
+	return t
+}
+
+func (c *Converter) handlePackage(p *pkg, event *Event) {
+	switch event.Action {
+	case ActionOutput:
+		c.echo(event.Output)
+		p.output.WriteString(event.Output)
+	case ActionPass, ActionSkip:
+		c.finishPackage(p, event.Time)
+		c.forget(p)
+	case ActionFail:
+		c.finishPackage(p, event.Time)
+
+		if !p.failed {
+			// the package has failed outside of its tests (e.g. it doesn't build or TestMain has failed)
+			c.print(c.packageResult(p, event))
+		}
+
+		c.forget(p)
+	}
+}
+
+// finishPackage marks the unfinished tests of the package as broken
+func (c *Converter) finishPackage(p *pkg, stop time.Time) {
+	// the subtests are finished before their parents
+	for i := len(p.tests) - 1; i >= 0; i-- {
+		if t := p.tests[i]; !t.done {
+			c.finish(t, allure.Broken, &Event{Time: stop, Output: p.output.String()})
+		}
+	}
+}
+
+func (c *Converter) forget(p *pkg) {
+	for _, t := range p.tests {
+		delete(c.tests, p.name+"\x00"+t.name)
+	}
+
+	delete(c.packages, p.name)
+}
+
+func (c *Converter) finish(t *test, status allure.Status, event *Event) {
+	if t.done {
+		return
+	}
+	t.done = true
+
+	failed := status == allure.Failed || status == allure.Broken
+	if failed && t.parent != nil {
+		t.parent.failed = true
+	}
+
+	// the subtests carry the result of the parent test, unless only the parent has failed
+	if t.children > 0 && !(failed && !t.failed) {
+		return
+	}
+
+	result := c.testResult(t, status, event)
+	if failed {
+		t.pkg.failed = true
+	}
+
+	c.print(result)
+}
+
+func (c *Converter) testResult(t *test, status allure.Status, event *Event) *allure.Result {
+	names := strings.Split(t.name, "/")
+
+	result := allure.NewResult(names[len(names)-1], t.pkg.name+"/"+t.name)
+	result.Status = status
+	result.AddLabel(
+		allure.PackageLabel(t.pkg.name),
+		allure.ParentSuiteLabel(t.pkg.name),
+		allure.SuiteLabel(names[0]),
+		allure.FrameWorkLabel(frameworkName),
+	)
+
+	if len(names) > 2 {
+		result.AddLabel(allure.SubSuiteLabel(strings.Join(names[1:len(names)-1], "/")))
+	}
+
+	setTimes(result, t.start, event)
+
+	output := t.output.String()
+	if status == allure.Broken && event.Output != "" {
+		output += event.Output
+	}
+
+	switch status {
+	case allure.Passed:
+	case allure.Broken:
+		result.StatusDetails = allure.StatusDetail{Message: "Test has not finished", Trace: output}
+	default:
+		result.StatusDetails = allure.StatusDetail{Message: message(output, "Test "+string(status)), Trace: output}
+	}
+
+	if output != "" {
+		result.Attachments = append(result.Attachments, allure.NewAttachment("output", allure.Text, []byte(output)))
+	}
+
+	return result
+}
+
+func (c *Converter) packageResult(p *pkg, event *Event) *allure.Result {
+	result := allure.NewResult(p.name, p.name)
+	result.Status = allure.Broken
+	result.AddLabel(
+		allure.PackageLabel(p.name),
+		allure.ParentSuiteLabel(p.name),
+		allure.FrameWorkLabel(frameworkName),
+	)
+
+	setTimes(result, time.Time{}, event)
+
+	output := p.output.String()
+	result.StatusDetails = allure.StatusDetail{Message: message(output, "Package failed"), Trace: output}
+
+	if output != "" {
+		result.Attachments = append(result.Attachments, allure.NewAttachment("output", allure.Text, []byte(output)))
+	}
+
+	return result
+}
+
+func (c *Converter) print(result *allure.Result) {
+	switch result.Status {
+	case allure.Passed:
+		c.summary.Passed++
+	case allure.Failed:
+		c.summary.Failed++
+	case allure.Broken:
+		c.summary.Broken++
+	case allure.Skipped:
+		c.summary.Skipped++
+	}
+
+	if err := result.WithSink(c.sink).Print(); err != nil {
+		c.errs = append(c.errs, errors.Wrapf(err, "Cannot print result of %s", result.FullName))
+	}
+}
+
+func (c *Converter) echo(output string) {
+	if c.Echo != nil {
+		_, _ = io.WriteString(c.Echo, output)
+	}
+}
+
+func setTimes(result *allure.Result, start time.Time, event *Event) {
+	stop := event.Time
+	if start.IsZero() && !stop.IsZero() {
+		start = stop.Add(-time.Duration(event.Elapsed * float64(time.Second)))
+	}
+
+	if !start.IsZero() {
+		result.Start = start.UnixNano() / int64(time.Millisecond)
+	}
+
+	if !stop.IsZero() {
+		result.Stop = stop.UnixNano() / int64(time.Millisecond)
+	} else {
+		result.Stop = result.Start
+	}
+}
+
+// isFraming returns true for the lines `go test -v` prints around the output of the test
+func isFraming(line string) bool {
+	trimmed := strings.TrimSpace(line)
+
+	for _, prefix := range []string{"=== RUN", "=== PAUSE", "=== CONT", "=== NAME", "--- PASS", "--- FAIL", "--- SKIP"} {
+		if strings.HasPrefix(trimmed, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// message returns the status message: the first not empty line of the output or the default message
+func message(output, defaultMessage string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+
+	return defaultMessage
+}
@@ -0,0 +1,142 @@
+package test2json
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ozontech/allure-go/pkg/allure"
+)
+
+const events = `{"Time":"2022-05-01T10:00:00Z","Action":"start","Package":"example/pkg"}
+{"Time":"2022-05-01T10:00:00Z","Action":"run","Package":"example/pkg","Test":"TestPassed"}
+{"Time":"2022-05-01T10:00:00Z","Action":"output","Package":"example/pkg","Test":"TestPassed","Output":"=== RUN   TestPassed\n"}
+{"Time":"2022-05-01T10:00:00Z","Action":"output","Package":"example/pkg","Test":"TestPassed","Output":"    pkg_test.go:10: some log\n"}
+{"Time":"2022-05-01T10:00:01Z","Action":"output","Package":"example/pkg","Test":"TestPassed","Output":"--- PASS: TestPassed (1.00s)\n"}
+{"Time":"2022-05-01T10:00:01Z","Action":"pass","Package":"example/pkg","Test":"TestPassed","Elapsed":1}
+not an event
+{"Time":"2022-05-01T10:00:01Z","Action":"run","Package":"example/pkg","Test":"TestTable"}
+{"Time":"2022-05-01T10:00:01Z","Action":"run","Package":"example/pkg","Test":"TestTable/group"}
+{"Time":"2022-05-01T10:00:01Z","Action":"run","Package":"example/pkg","Test":"TestTable/group/case_1"}
+{"Time":"2022-05-01T10:00:01Z","Action":"output","Package":"example/pkg","Test":"TestTable/group/case_1","Output":"    pkg_test.go:20: expected 1, got 2\n"}
+{"Time":"2022-05-01T10:00:01Z","Action":"fail","Package":"example/pkg","Test":"TestTable/group/case_1","Elapsed":0}
+{"Time":"2022-05-01T10:00:01Z","Action":"run","Package":"example/pkg","Test":"TestTable/group/case_2"}
+{"Time":"2022-05-01T10:00:01Z","Action":"output","Package":"example/pkg","Test":"TestTable/group/case_2","Output":"    pkg_test.go:30: not supported\n"}
+{"Time":"2022-05-01T10:00:01Z","Action":"skip","Package":"example/pkg","Test":"TestTable/group/case_2","Elapsed":0}
+{"Time":"2022-05-01T10:00:01Z","Action":"fail","Package":"example/pkg","Test":"TestTable/group","Elapsed":0}
+{"Time":"2022-05-01T10:00:01Z","Action":"fail","Package":"example/pkg","Test":"TestTable","Elapsed":0}
+{"Time":"2022-05-01T10:00:01Z","Action":"run","Package":"example/pkg","Test":"TestParentFailed"}
+{"Time":"2022-05-01T10:00:01Z","Action":"run","Package":"example/pkg","Test":"TestParentFailed/sub"}
+{"Time":"2022-05-01T10:00:01Z","Action":"pass","Package":"example/pkg","Test":"TestParentFailed/sub","Elapsed":0}
+{"Time":"2022-05-01T10:00:01Z","Action":"output","Package":"example/pkg","Test":"TestParentFailed","Output":"    pkg_test.go:40: cleanup failed\n"}
+{"Time":"2022-05-01T10:00:01Z","Action":"fail","Package":"example/pkg","Test":"TestParentFailed","Elapsed":0}
+{"Time":"2022-05-01T10:00:02Z","Action":"run","Package":"example/pkg","Test":"TestPanicked"}
+{"Time":"2022-05-01T10:00:02Z","Action":"output","Package":"example/pkg","Output":"panic: whoops\n"}
+{"Time":"2022-05-01T10:00:02Z","Action":"output","Package":"example/pkg","Output":"FAIL\texample/pkg\t2.000s\n"}
+{"Time":"2022-05-01T10:00:02Z","Action":"fail","Package":"example/pkg","Elapsed":2}
+{"Time":"2022-05-01T10:00:00Z","Action":"output","Package":"example/broken","Output":"# example/broken\n"}
+{"Time":"2022-05-01T10:00:00Z","Action":"output","Package":"example/broken","Output":"broken.go:3:1: syntax error\n"}
+{"Time":"2022-05-01T10:00:00Z","Action":"fail","Package":"example/broken","Elapsed":0}
+{"Time":"2022-05-01T10:00:00Z","Action":"output","Package":"example/empty","Output":"?   \texample/empty\t[no test files]\n"}
+{"Time":"2022-05-01T10:00:00Z","Action":"skip","Package":"example/empty","Elapsed":0}
+`
+
+func resultsByName(t *testing.T, sink *allure.MemorySink) map[string]*allure.Result {
+	list, err := sink.Results()
+	require.NoError(t, err)
+
+	results := make(map[string]*allure.Result, len(list))
+	for _, result := range list {
+		results[result.FullName] = result
+	}
+
+	return results
+}
+
+func labelValue(result *allure.Result, labelType allure.LabelType) string {
+	if label, ok := result.GetFirstLabel(labelType); ok {
+		return label.GetValue()
+	}
+
+	return ""
+}
+
+func TestConverter_Convert(t *testing.T) {
+	sink := allure.NewMemorySink()
+
+	var echo bytes.Buffer
+	converter := NewConverter(sink)
+	converter.Echo = &echo
+
+	require.NoError(t, converter.Convert(strings.NewReader(events)))
+	require.Equal(t, Summary{Passed: 2, Failed: 2, Broken: 2, Skipped: 1}, converter.Summary())
+	require.Contains(t, echo.String(), "--- PASS: TestPassed (1.00s)\n")
+
+	results := resultsByName(t, sink)
+	require.Len(t, results, 7)
+
+	passed := results["example/pkg/TestPassed"]
+	require.Equal(t, allure.Passed, passed.Status)
+	require.Equal(t, "TestPassed", passed.Name)
+	require.Equal(t, "example/pkg", labelValue(passed, allure.Package))
+	require.Equal(t, "example/pkg", labelValue(passed, allure.ParentSuite))
+	require.Equal(t, "TestPassed", labelValue(passed, allure.Suite))
+	require.Equal(t, "", labelValue(passed, allure.SubSuite))
+	require.Equal(t, int64(1000), passed.Stop-passed.Start)
+	require.Len(t, passed.Attachments, 1)
+
+	output, ok := sink.File(passed.Attachments[0].Source)
+	require.True(t, ok)
+	require.Equal(t, "    pkg_test.go:10: some log\n", string(output))
+
+	failed := results["example/pkg/TestTable/group/case_1"]
+	require.Equal(t, allure.Failed, failed.Status)
+	require.Equal(t, "case_1", failed.Name)
+	require.Equal(t, "TestTable", labelValue(failed, allure.Suite))
+	require.Equal(t, "group", labelValue(failed, allure.SubSuite))
+	require.Equal(t, "pkg_test.go:20: expected 1, got 2", failed.StatusDetails.Message)
+
+	skipped := results["example/pkg/TestTable/group/case_2"]
+	require.Equal(t, allure.Skipped, skipped.Status)
+	require.Equal(t, "pkg_test.go:30: not supported", skipped.StatusDetails.Message)
+
+	parentFailed := results["example/pkg/TestParentFailed"]
+	require.Equal(t, allure.Failed, parentFailed.Status)
+	require.Equal(t, "pkg_test.go:40: cleanup failed", parentFailed.StatusDetails.Message)
+
+	panicked := results["example/pkg/TestPanicked"]
+	require.Equal(t, allure.Broken, panicked.Status)
+	require.Equal(t, "Test has not finished", panicked.StatusDetails.Message)
+	require.Contains(t, panicked.StatusDetails.Trace, "panic: whoops")
+
+	broken := results["example/broken"]
+	require.Equal(t, allure.Broken, broken.Status)
+	require.Equal(t, "# example/broken", broken.StatusDetails.Message)
+	require.Contains(t, broken.StatusDetails.Trace, "syntax error")
+}
+
+func TestConverter_Flush(t *testing.T) {
+	sink := allure.NewMemorySink()
+
+	converter := NewConverter(sink)
+	converter.Handle(&Event{Action: ActionRun, Package: "example/pkg", Test: "TestKilled"})
+	converter.Flush()
+
+	results := resultsByName(t, sink)
+	require.Len(t, results, 1)
+	require.Equal(t, allure.Broken, results["example/pkg/TestKilled"].Status)
+}
+
+func TestConverter_Valid(t *testing.T) {
+	sink := allure.NewMemorySink()
+	require.NoError(t, NewConverter(sink).Convert(strings.NewReader(events)))
+
+	results, err := sink.Results()
+	require.NoError(t, err)
+
+	for _, result := range results {
+		require.NoError(t, result.Validate())
+	}
+}
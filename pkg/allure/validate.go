@@ -0,0 +1,327 @@
+package allure
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// ValidationMode defines what happens when the printed Result or Container doesn't match the Allure 2 result schema
+type ValidationMode int
+
+// ValidationMode constants
+const (
+	ValidationOff     ValidationMode = iota // Results are not validated
+	ValidationLenient                       // A warning is printed, the result is written as is
+	ValidationStrict                        // The result is written as is, but Print returns the validation error (the framework fails the test)
+)
+
+// String returns the name of the mode as it is set in `$ALLURE_VALIDATION`
+func (mode ValidationMode) String() string {
+	switch mode {
+	case ValidationOff:
+		return "off"
+	case ValidationStrict:
+		return "strict"
+	default:
+		return "lenient"
+	}
+}
+
+// Result stages of the Allure 2 result schema
+var validStages = map[string]bool{
+	"scheduled":   true,
+	"running":     true,
+	"finished":    true,
+	"pending":     true,
+	"interrupted": true,
+}
+
+var (
+	validationMu   sync.RWMutex
+	validationMode = validationModeFromEnv()
+)
+
+// SetValidationMode sets the process-wide ValidationMode.
+// By default, it is taken from `$ALLURE_VALIDATION` (`off`, `lenient` or `strict`), `lenient` if it is not set.
+func SetValidationMode(mode ValidationMode) {
+	validationMu.Lock()
+	defer validationMu.Unlock()
+
+	validationMode = mode
+}
+
+// GetValidationMode returns the process-wide ValidationMode
+func GetValidationMode() ValidationMode {
+	validationMu.RLock()
+	defer validationMu.RUnlock()
+
+	return validationMode
+}
+
+func validationModeFromEnv() ValidationMode {
+	switch strings.ToLower(strings.TrimSpace(configValue(validationEnvKey))) {
+	case "off":
+		return ValidationOff
+	case "strict":
+		return ValidationStrict
+	default:
+		return ValidationLenient
+	}
+}
+
+// ValidationError is the list of mismatches of the entity with the Allure 2 result schema
+type ValidationError struct {
+	Entity string   // Name of the validated file (e.g. `<uuid>-result.json`)
+	Issues []string // Mismatches with the path of the field (e.g. `steps[0].status: unknown status "done"`)
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s doesn't match Allure result schema: %s", e.Entity, strings.Join(e.Issues, "; "))
+}
+
+// validator collects the issues of the entity
+type validator struct {
+	issues []string
+}
+
+func (v *validator) addf(path, format string, args ...interface{}) {
+	v.issues = append(v.issues, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (v *validator) err(entity string) error {
+	if len(v.issues) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Entity: entity, Issues: v.issues}
+}
+
+// Validate checks the Result against the Allure 2 result schema:
+// the result must have the UUID, the name and a known status, it must not stop before it starts,
+// and all its labels, links, parameters, attachments and steps must be valid too.
+// Returns *ValidationError with all found issues.
+func (result *Result) Validate() error {
+	return result.validate(nil)
+}
+
+// validate validates the result, if sink is not nil, the files of the written attachments are checked too
+func (result *Result) validate(sink Sink) error {
+	result.m.RLock()
+	defer result.m.RUnlock()
+
+	v := new(validator)
+
+	if result.UUID == uuid.Nil {
+		v.addf("uuid", "is empty")
+	}
+
+	if result.Name == "" {
+		v.addf("name", "is empty")
+	}
+
+	if result.Status == "" {
+		v.addf("status", "is empty")
+	} else {
+		v.status("status", result.Status)
+	}
+
+	if result.Stage != "" && !validStages[result.Stage] {
+		v.addf("stage", "unknown stage %q", result.Stage)
+	}
+
+	v.times("", result.Start, result.Stop)
+
+	for i, label := range result.Labels {
+		if label == nil || label.Name == "" {
+			v.addf(fmt.Sprintf("labels[%d].name", i), "is empty")
+		}
+	}
+
+	for i, link := range result.Links {
+		if link == nil || link.URL == "" {
+			v.addf(fmt.Sprintf("links[%d].url", i), "is empty")
+		}
+	}
+
+	v.parameters("", result.Parameters)
+	v.attachments("", result.Attachments)
+	v.steps("steps", result.Steps)
+
+	if sink != nil {
+		v.missingAttachments(sink, result.Attachments, result.Steps)
+	}
+
+	return v.err(result.UUID.String() + resultFileSuffix)
+}
+
+// Validate checks the Container against the Allure 2 result schema:
+// the container must have the UUID, the children must not be empty UUIDs, it must not stop before it starts,
+// and all its steps must be valid too.
+// Returns *ValidationError with all found issues.
+func (container *Container) Validate() error {
+	return container.validate(nil)
+}
+
+// validate validates the container, if sink is not nil, the files of the written attachments are checked too
+func (container *Container) validate(sink Sink) error {
+	v := new(validator)
+
+	if container.UUID == uuid.Nil {
+		v.addf("uuid", "is empty")
+	}
+
+	for i, child := range container.Children {
+		if child == uuid.Nil {
+			v.addf(fmt.Sprintf("children[%d]", i), "is empty")
+		}
+	}
+
+	v.times("", container.Start, container.Stop)
+	v.steps("befores", container.Befores)
+	v.steps("afters", container.Afters)
+
+	if sink != nil {
+		v.missingAttachments(sink, nil, container.Befores)
+		v.missingAttachments(sink, nil, container.Afters)
+	}
+
+	return v.err(container.UUID.String() + containerFileSuffix)
+}
+
+func (v *validator) status(path string, status Status) {
+	switch status {
+	case Passed, Failed, Broken, Skipped, Unknown:
+	default:
+		v.addf(path, "unknown status %q", status)
+	}
+}
+
+func (v *validator) times(prefix string, start, stop int64) {
+	if start < 0 {
+		v.addf(prefix+"start", "is negative")
+	}
+
+	if stop != 0 && stop < start {
+		v.addf(prefix+"stop", "%d is before start %d", stop, start)
+	}
+}
+
+func (v *validator) parameters(prefix string, parameters []*Parameter) {
+	for i, param := range parameters {
+		if param == nil || param.Name == "" {
+			v.addf(fmt.Sprintf("%sparameters[%d].name", prefix, i), "is empty")
+		}
+	}
+}
+
+func (v *validator) attachments(prefix string, attachments []*Attachment) {
+	for i, attachment := range attachments {
+		if attachment == nil || attachment.Source == "" {
+			v.addf(fmt.Sprintf("%sattachments[%d].source", prefix, i), "is empty")
+		}
+	}
+}
+
+func (v *validator) steps(path string, steps []*Step) {
+	for i, step := range steps {
+		stepPath := fmt.Sprintf("%s[%d]", path, i)
+		if step == nil {
+			v.addf(stepPath, "is nil")
+			continue
+		}
+
+		if step.Name == "" {
+			v.addf(stepPath+".name", "is empty")
+		}
+
+		if step.Status != "" {
+			v.status(stepPath+".status", step.Status)
+		}
+
+		v.times(stepPath+".", step.Start, step.Stop)
+		v.parameters(stepPath+".", step.Parameters)
+		v.attachments(stepPath+".", step.Attachments)
+		v.steps(stepPath+".steps", step.Steps)
+	}
+}
+
+// missingAttachments adds the issues of the attachments written before the print (streamed and file-backed ones)
+// whose files the sink doesn't have, e.g. because they were written to another sink
+func (v *validator) missingAttachments(sink Sink, attachments []*Attachment, steps []*Step) {
+	check := func(list []*Attachment) {
+		for _, attachment := range list {
+			if attachment == nil || !attachment.written || attachment.Source == "" {
+				continue
+			}
+
+			if ok, known := hasFile(sink, attachment.Source); known && !ok {
+				v.addf("attachments", "file %s of %q is missing", attachment.Source, attachment.Name)
+			}
+		}
+	}
+
+	check(attachments)
+	walkSteps(steps, func(step *Step) { check(step.Attachments) })
+}
+
+// hasFile returns true if the sink has the file, known is false if the sink can't tell it
+func hasFile(sink Sink, name string) (ok, known bool) {
+	switch s := sink.(type) {
+	case *Batch:
+		return hasFile(sinkOrDefault(s.sink), name)
+	case *MemorySink:
+		_, ok = s.File(name)
+		return ok, true
+	case *multiSink:
+		for _, inner := range s.sinks {
+			if ok, known = hasFile(inner, name); known && !ok {
+				return false, true
+			}
+		}
+
+		return true, true
+	case dirSink:
+		dir, err := s.outputDir()
+		if err != nil {
+			return false, false
+		}
+
+		_, err = os.Stat(filepath.Join(dir, name))
+
+		return err == nil, true
+	default:
+		return false, false
+	}
+}
+
+func walkSteps(steps []*Step, fn func(step *Step)) {
+	for _, step := range steps {
+		if step == nil {
+			continue
+		}
+
+		fn(step)
+		walkSteps(step.Steps, fn)
+	}
+}
+
+// applyValidation applies the process-wide ValidationMode to the validation error of the printed entity.
+// Returns the error in the strict mode only, in the lenient mode the warning is printed instead.
+func applyValidation(validationErr error) error {
+	if validationErr == nil {
+		return nil
+	}
+
+	if GetValidationMode() == ValidationStrict {
+		return validationErr
+	}
+
+	fmt.Printf("allure: %s\n", validationErr)
+
+	return nil
+}
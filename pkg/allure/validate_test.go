@@ -0,0 +1,129 @@
+package allure
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func validationIssues(t *testing.T, err error) []string {
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+
+	return validationErr.Issues
+}
+
+func TestResult_Validate(t *testing.T) {
+	result := NewResult(testName, testFullName)
+	result.Status = Passed
+	result.Finish()
+	result.Steps = append(result.Steps, NewSimpleStep("step", NewParameter("key", "value")))
+	result.Attachments = append(result.Attachments, NewAttachment("attach", Text, []byte("text")))
+	require.NoError(t, result.Validate())
+
+	invalid := &Result{Stage: "done", Start: 20, Stop: 10}
+	invalid.Labels = append(invalid.Labels, &Label{Value: "value"})
+	invalid.Links = append(invalid.Links, &Link{Name: "link"})
+	invalid.Attachments = append(invalid.Attachments, &Attachment{Name: "attach"})
+	step := NewStep("", "done", 20, 10, []*Parameter{{Value: "value"}})
+	step.Steps = append(step.Steps, nil)
+	invalid.Steps = append(invalid.Steps, step)
+
+	err := invalid.Validate()
+	require.Error(t, err)
+	require.True(t, strings.HasPrefix(err.Error(), uuid.Nil.String()+"-result.json doesn't match Allure result schema: "))
+	require.Equal(t, []string{
+		"uuid: is empty",
+		"name: is empty",
+		"status: is empty",
+		`stage: unknown stage "done"`,
+		"stop: 10 is before start 20",
+		"labels[0].name: is empty",
+		"links[0].url: is empty",
+		"attachments[0].source: is empty",
+		"steps[0].name: is empty",
+		`steps[0].status: unknown status "done"`,
+		"steps[0].stop: 10 is before start 20",
+		"steps[0].parameters[0].name: is empty",
+		"steps[0].steps[0]: is nil",
+	}, validationIssues(t, err))
+}
+
+func TestContainer_Validate(t *testing.T) {
+	container := NewContainer()
+	container.AddChild(uuid.New())
+	container.Befores = append(container.Befores, NewSimpleStep("before"))
+	container.Begin()
+	container.Finish()
+	require.NoError(t, container.Validate())
+
+	invalid := &Container{Children: []uuid.UUID{uuid.Nil}, Start: 20, Stop: 10}
+	invalid.Afters = append(invalid.Afters, NewStep("after", Unknown, 10, 0, nil))
+	require.Equal(t, []string{
+		"uuid: is empty",
+		"children[0]: is empty",
+		"stop: 10 is before start 20",
+	}, validationIssues(t, invalid.Validate()))
+}
+
+func TestResult_PrintValidation(t *testing.T) {
+	defer SetValidationMode(GetValidationMode())
+
+	sink := NewMemorySink()
+	result := NewResult(testName, testFullName).WithSink(sink)
+	result.Status = "done"
+
+	SetValidationMode(ValidationLenient)
+	require.NoError(t, result.Print())
+	require.Len(t, sink.Files(), 1)
+
+	SetValidationMode(ValidationStrict)
+	err := result.Print()
+	require.Equal(t, []string{`status: unknown status "done"`}, validationIssues(t, err))
+	// the invalid result is written anyway
+	require.Len(t, sink.Files(), 1)
+
+	SetValidationMode(ValidationOff)
+	require.NoError(t, result.Print())
+}
+
+func TestResult_PrintValidationMissingAttachment(t *testing.T) {
+	defer SetValidationMode(GetValidationMode())
+	SetValidationMode(ValidationStrict)
+
+	SetSink(NewMemorySink())
+	defer SetSink(nil)
+
+	// the attachment is written to the process-wide sink, while the result is printed to its own one
+	attachment, err := NewAttachmentFromReader("attach", Text, strings.NewReader("text"))
+	require.NoError(t, err)
+
+	result := NewResult(testName, testFullName).WithSink(NewMemorySink())
+	result.Attachments = append(result.Attachments, attachment)
+	require.Equal(t,
+		[]string{`attachments: file ` + attachment.Source + ` of "attach" is missing`},
+		validationIssues(t, result.Done()),
+	)
+
+	require.NoError(t, result.WithSink(GetSink()).Done())
+}
+
+func TestValidationModeFromEnv(t *testing.T) {
+	for value, mode := range map[string]ValidationMode{
+		"":        ValidationLenient,
+		"off":     ValidationOff,
+		"Strict":  ValidationStrict,
+		"lenient": ValidationLenient,
+		"unknown": ValidationLenient,
+	} {
+		t.Setenv(validationEnvKey, value)
+		require.Equal(t, mode, validationModeFromEnv(), value)
+	}
+
+	require.Equal(t, "off", ValidationOff.String())
+	require.Equal(t, "lenient", ValidationLenient.String())
+	require.Equal(t, "strict", ValidationStrict.String())
+}
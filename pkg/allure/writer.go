@@ -0,0 +1,207 @@
+package allure
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// defaultWriterQueueSize is the queue size of the shared Writer
+const defaultWriterQueueSize = 1024
+
+var (
+	defaultWriterOnce sync.Once
+	defaultWriter     *Writer
+)
+
+// DefaultWriter returns the shared Writer with one worker per CPU.
+// The Writer is created on the first call.
+func DefaultWriter() *Writer {
+	defaultWriterOnce.Do(func() {
+		defaultWriter = NewWriter(runtime.NumCPU(), defaultWriterQueueSize)
+	})
+
+	return defaultWriter
+}
+
+// Writer writes files asynchronously with a bounded queue and a pool of workers.
+// Files are enqueued with a Batch, which allows waiting for and collecting errors
+// of only its own files while the workers are shared.
+type Writer struct {
+	queue chan *writeJob
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+type writeJob struct {
+	batch   *Batch
+	name    string
+	content []byte
+}
+
+// NewWriter returns pointer to the new Writer with the given number of workers and queue size.
+// If the queue is full, enqueueing blocks until one of the workers is free.
+func NewWriter(workers, queueSize int) *Writer {
+	if workers < 1 {
+		workers = 1
+	}
+
+	if queueSize < 0 {
+		queueSize = 0
+	}
+
+	w := &Writer{queue: make(chan *writeJob, queueSize)}
+
+	w.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go w.work()
+	}
+
+	return w
+}
+
+// NewBatch returns pointer to the new Batch that writes files to the passed Sink.
+// If sink is nil, the process-wide Sink is used.
+func (w *Writer) NewBatch(sink Sink) *Batch {
+	return &Batch{writer: w, sink: sink}
+}
+
+// Close stops the workers after all enqueued files are written.
+// Files enqueued after Close are written synchronously.
+func (w *Writer) Close() {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return
+	}
+	w.closed = true
+	close(w.queue)
+	w.mu.Unlock()
+
+	w.wg.Wait()
+}
+
+func (w *Writer) work() {
+	defer w.wg.Done()
+
+	for job := range w.queue {
+		job.batch.write(job.name, job.content)
+	}
+}
+
+// enqueue returns false if the Writer is closed
+func (w *Writer) enqueue(job *writeJob) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return false
+	}
+
+	w.queue <- job
+
+	return true
+}
+
+// Batch is a Sink that enqueues files to the Writer.
+// Use Flush to wait until all files of the batch are written.
+type Batch struct {
+	writer *Writer
+	sink   Sink
+
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs []error
+}
+
+// CreateFile enqueues the file to the Writer. The error of writing is returned by Batch.Flush
+func (b *Batch) CreateFile(name string, content []byte) error {
+	b.wg.Add(1)
+
+	if !b.writer.enqueue(&writeJob{batch: b, name: name, content: content}) {
+		b.write(name, content)
+	}
+
+	return nil
+}
+
+// CreateFileFrom writes the file from the reader synchronously, since the reader can't outlive the call
+func (b *Batch) CreateFileFrom(name string, content io.Reader) error {
+	return createFileFrom(sinkOrDefault(b.sink), name, content)
+}
+
+// mergeFile merges the file synchronously, since launch files are shared with other batches and processes
+func (b *Batch) mergeFile(name string, merge mergeFunc) error {
+	return mergeFile(sinkOrDefault(b.sink), name, merge)
+}
+
+// Flush waits until all enqueued files of the batch are written and returns their errors (if any)
+func (b *Batch) Flush() error {
+	b.wg.Wait()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	err := joinErrors(b.errs)
+	b.errs = nil
+
+	return err
+}
+
+func (b *Batch) write(name string, content []byte) {
+	defer b.wg.Done()
+
+	if err := sinkOrDefault(b.sink).CreateFile(name, content); err != nil {
+		b.mu.Lock()
+		b.errs = append(b.errs, errors.Wrapf(err, "Cannot write %s", name))
+		b.mu.Unlock()
+	}
+}
+
+// writeFileAtomic writes content to a temporary file in the same directory and renames it to path,
+// so readers never see a partially written file
+func writeFileAtomic(path string, content []byte) error {
+	return writeFromAtomic(path, bytes.NewReader(content))
+}
+
+// writeFromAtomic copies content to a temporary file in the same directory and renames it to path,
+// so readers never see a partially written file
+func writeFromAtomic(path string, content io.Reader) error {
+	dir, name := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+name+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	tmpName := tmp.Name()
+
+	_, err = io.Copy(tmp, content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Chmod(tmpName, fileSystemPermissionCode)
+	}
+
+	if err == nil {
+		err = os.Rename(tmpName, path)
+	}
+
+	if err != nil {
+		_ = os.Remove(tmpName)
+	}
+
+	return err
+}
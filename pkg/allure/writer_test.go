@@ -0,0 +1,80 @@
+package allure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultWriter(t *testing.T) {
+	require.NotNil(t, DefaultWriter())
+	require.Equal(t, DefaultWriter(), DefaultWriter())
+}
+
+func TestBatch_Flush(t *testing.T) {
+	writer := NewWriter(4, 2)
+	defer writer.Close()
+
+	sink := NewMemorySink()
+	batch := writer.NewBatch(sink)
+
+	for i := 0; i < 100; i++ {
+		require.NoError(t, batch.CreateFile(fmt.Sprintf("%d.txt", i), []byte("text")))
+	}
+
+	require.NoError(t, batch.Flush())
+	require.Len(t, sink.Files(), 100)
+}
+
+func TestBatch_FlushErrors(t *testing.T) {
+	writer := NewWriter(1, 0)
+	defer writer.Close()
+
+	failed := writer.NewBatch(&sinkMock{err: errors.New("whoops")})
+	succeeded := writer.NewBatch(&sinkMock{})
+
+	require.NoError(t, failed.CreateFile("failed.txt", nil))
+	require.NoError(t, succeeded.CreateFile("succeeded.txt", nil))
+
+	require.EqualError(t, failed.Flush(), "Cannot write failed.txt: whoops")
+	require.NoError(t, succeeded.Flush())
+	require.NoError(t, failed.Flush())
+}
+
+func TestBatch_AfterClose(t *testing.T) {
+	writer := NewWriter(1, 1)
+	writer.Close()
+	writer.Close()
+
+	sink := NewMemorySink()
+	batch := writer.NewBatch(sink)
+	require.NoError(t, batch.CreateFile("test.txt", []byte("text")))
+	require.NoError(t, batch.Flush())
+
+	_, ok := sink.File("test.txt")
+	require.True(t, ok)
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.txt")
+
+	require.NoError(t, writeFileAtomic(path, []byte("first")))
+	require.NoError(t, writeFileAtomic(path, []byte("second")))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "second", string(content))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(fileSystemPermissionCode), info.Mode().Perm())
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+}
@@ -10,6 +10,12 @@ type Provider interface {
 	Step(step *allure.Step)
 }
 
+// MaskingProvider is the Provider knowing the values of the masked parameters of its test,
+// the assertion parameters containing them are masked (see allure.MaskKnownValues)
+type MaskingProvider interface {
+	MaskedValues() []string
+}
+
 type assertHelper struct {
 	prefix string
 }
@@ -22,8 +28,13 @@ func (h *assertHelper) getStepName(assertName string, msgAndArgs ...interface{})
 }
 
 func (h *assertHelper) WithNewStep(t TestingT, provider Provider, assertName string, assert func(t TestingT) bool, params []*allure.Parameter, msgAndArgs ...interface{}) bool {
+	var masked []string
+	if maskingProvider, ok := provider.(MaskingProvider); ok {
+		masked = maskingProvider.MaskedValues()
+	}
+
 	var (
-		step   = allure.NewSimpleStep(h.getStepName(assertName, msgAndArgs...), params...)
+		step   = allure.NewSimpleStep(h.getStepName(assertName, msgAndArgs...), allure.MaskKnownValues(masked, params...)...)
 		result = assert(t)
 	)
 
@@ -97,3 +97,33 @@ func TestAssertHelper_withNewStep_requireTrue(t *testing.T) {
 	require.Equal(t, param2.Name, mock2.steps[0].Parameters[0].Name)
 	require.Equal(t, param2.GetValue(), mock2.steps[0].Parameters[0].GetValue())
 }
+
+type maskingTMock struct {
+	*tMock
+
+	masked []string
+}
+
+func (p *maskingTMock) MaskedValues() []string {
+	return p.masked
+}
+
+func TestAssertHelper_withNewStep_maskedValue(t *testing.T) {
+	a := &assertHelper{prefix: "ASSERT"}
+
+	// the provider knows no masked values, e.g. the token is masked by another test
+	other := newTMock()
+	a.WithNewStep(other, other, "Test", func(t TestingT) bool { return true }, allure.NewParameters("Expected", "helper-secret-token"))
+	require.False(t, other.steps[0].Parameters[0].IsMasked())
+
+	mock := &maskingTMock{tMock: newTMock(), masked: []string{"helper-secret-token"}}
+	params := allure.NewParameters("Expected", `"helper-secret-token"`, "Actual", "other")
+	result := a.WithNewStep(mock, mock, "Test", func(t TestingT) bool { return false }, params)
+	require.False(t, result)
+	require.Len(t, mock.steps, 1)
+	require.Len(t, mock.steps[0].Parameters, 2)
+	require.True(t, mock.steps[0].Parameters[0].IsMasked())
+	require.Equal(t, allure.MaskedValue, mock.steps[0].Parameters[0].GetValue())
+	require.False(t, mock.steps[0].Parameters[1].IsMasked())
+	require.Equal(t, "other", mock.steps[0].Parameters[1].GetValue())
+}
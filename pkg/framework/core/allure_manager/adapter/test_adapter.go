@@ -42,6 +42,7 @@ func NewTestMeta(suiteFullName, suiteName, testName, packageName string, tags ..
 		WithThread(fullName).
 		WithLanguage(runtime.Version()).
 		WithLaunchTags().
+		WithDefaultLabels().
 		WithSuite(suiteName).
 		WithPackage(packageName).
 		WithLabels(newTags...)
@@ -52,6 +53,15 @@ func NewTestMeta(suiteFullName, suiteName, testName, packageName string, tags ..
 	return &TestAdapter{result: result, container: container}
 }
 
+// NewRetryTestMeta returns pointer to instance of TestAdapter of the next attempt of the test
+// with the passed result (see allure.Result.Retry) and a new container
+func NewRetryTestMeta(result *allure.Result) *TestAdapter {
+	container := allure.NewContainer()
+	container.AddChild(result.UUID)
+
+	return &TestAdapter{result: result, container: container}
+}
+
 // GetResult returns allure.Result pointer
 func (ctx *TestAdapter) GetResult() *allure.Result {
 	return ctx.result
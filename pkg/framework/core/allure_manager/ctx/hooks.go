@@ -49,6 +49,11 @@ func (ctx *hooksCtx) GetName() string {
 	return ctx.name
 }
 
+// GetSink returns the sink the container of the context is printed to
+func (ctx *hooksCtx) GetSink() allure.Sink {
+	return ctx.container.GetSink()
+}
+
 // AddAttachments adds attachment to the execution context
 func (ctx *hooksCtx) AddAttachments(attachments ...*allure.Attachment) {
 	if len(attachments) == 0 {
@@ -24,6 +24,11 @@ func (ctx *testCtx) GetName() string {
 	return ctx.name
 }
 
+// GetSink returns the sink the result of the context is printed to
+func (ctx *testCtx) GetSink() allure.Sink {
+	return ctx.result.GetSink()
+}
+
 func (ctx *testCtx) AddAttachments(attachments ...*allure.Attachment) {
 	ctx.result.Attachments = append(ctx.result.Attachments, attachments...)
 }
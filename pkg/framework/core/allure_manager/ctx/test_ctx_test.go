@@ -18,6 +18,15 @@ func TestTestCtx_GetName(t *testing.T) {
 	require.Equal(t, "test", th.GetName())
 }
 
+func TestTestCtx_GetSink(t *testing.T) {
+	sink := allure.NewMemorySink()
+	test := testCtx{name: constants.TestContextName, result: (&allure.Result{}).WithSink(sink)}
+	require.Equal(t, sink, test.GetSink())
+
+	test = testCtx{name: constants.TestContextName, result: &allure.Result{}}
+	require.Equal(t, allure.GetSink(), test.GetSink())
+}
+
 func TestTestCtx_AddStep(t *testing.T) {
 	testStep := allure.NewSimpleStep("test")
 	test := testCtx{name: constants.TestContextName, result: &allure.Result{}}
@@ -0,0 +1,353 @@
+package labelfilter
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ozontech/allure-go/pkg/allure"
+)
+
+var labelsFlag = flag.String("allure-go.labels", "", "label expression to select the allure-go tests to run (e.g. 'tag=smoke && !tag=slow'), $ALLURE_LABELS if not set")
+
+var (
+	once        = sync.Once{}
+	labelFilter *Filter
+)
+
+// bodyLabels are the labels set only by the test body (e.g. `t.Severity(...)`), they are not known before the test is run
+// unless the suite declares them with GetLabels
+var bodyLabels = map[string]bool{
+	string(allure.Severity): true,
+	string(allure.Epic):     true,
+	string(allure.Feature):  true,
+	string(allure.Story):    true,
+	string(allure.Layer):    true,
+	string(allure.Owner):    true,
+	string(allure.Lead):     true,
+}
+
+// Filter is the parsed label expression selecting the tests by the labels of their results.
+//
+// The expression consists of the conditions on the labels:
+//
+//	name=value           the test has the label with the value
+//	name!=value          the test has no label with the value
+//	name in (v1, v2)     the test has the label with any of the values
+//	name                 the test has the label with any value
+//
+// combined with `!`, `&&`, `||` and parentheses, e.g. `tag=smoke && severity in (critical,blocker) && !tag=slow`.
+// The values containing spaces or special characters are quoted with `"` or `'`.
+type Filter struct {
+	expr   node
+	source string
+	labels []string
+}
+
+// Parse parses the label expression
+func Parse(expr string) (*Filter, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label expression %q: %w", expr, err)
+	}
+
+	p := &parser{tokens: tokens}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid label expression %q: %w", expr, err)
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("invalid label expression %q: unexpected %s", expr, tok)
+	}
+
+	return &Filter{expr: root, source: expr, labels: p.labels()}, nil
+}
+
+// Match returns true if the labels of the result satisfy the expression. Nil filter matches every result.
+func (f *Filter) Match(result *allure.Result) bool {
+	if f == nil {
+		return true
+	}
+
+	return f.expr.match(result.Labels)
+}
+
+// CheckLabels returns the error if the expression refers to the labels set only by the test body (e.g. `severity`):
+// the filter is evaluated before the test is run, so it would never select the test by them.
+// declared is true if the labels of the tests are declared before the run (GetLabels of the suite).
+func (f *Filter) CheckLabels(declared bool) error {
+	if f == nil || declared {
+		return nil
+	}
+
+	var names []string
+	for _, name := range f.labels {
+		if bodyLabels[name] {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("label expression %q refers to %s set by the test body, the labels are not known before the test is run: "+
+		"declare them with GetLabels(testName) of the suite or select the tests by the tags", f.source, strings.Join(names, ", "))
+}
+
+// String returns the source of the expression
+func (f *Filter) String() string {
+	return f.source
+}
+
+// GetLabelFilter returns the label filter of the process taken from `-allure-go.labels` flag or `$ALLURE_LABELS`
+// (`labelFilter` of the allure-go config file), nil if it is not set. The invalid expression stops the process.
+func GetLabelFilter() *Filter {
+	once.Do(func() {
+		expr := *labelsFlag
+		if expr == "" {
+			expr = allure.GetConfig().LabelFilter
+		}
+
+		if strings.TrimSpace(expr) == "" {
+			return
+		}
+
+		filter, err := Parse(expr)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "allure-go: %s\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Label filter found. Tests are selected by %s\n", filter)
+		labelFilter = filter
+	})
+
+	return labelFilter
+}
+
+type node interface {
+	match(labels []*allure.Label) bool
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n notNode) match(labels []*allure.Label) bool {
+	return !n.operand.match(labels)
+}
+
+type andNode struct {
+	left, right node
+}
+
+func (n andNode) match(labels []*allure.Label) bool {
+	return n.left.match(labels) && n.right.match(labels)
+}
+
+type orNode struct {
+	left, right node
+}
+
+func (n orNode) match(labels []*allure.Label) bool {
+	return n.left.match(labels) || n.right.match(labels)
+}
+
+// labelNode is the condition on the labels of the name, any value matches if values are empty
+type labelNode struct {
+	name   string
+	values []string
+}
+
+func (n labelNode) match(labels []*allure.Label) bool {
+	for _, label := range labels {
+		if label == nil || label.Name != n.name {
+			continue
+		}
+
+		if len(n.values) == 0 {
+			return true
+		}
+
+		for _, value := range n.values {
+			if label.GetValue() == value {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// parser is the recursive descent parser of the expression:
+//
+//	or    = and { "||" and }
+//	and   = unary { "&&" unary }
+//	unary = "!" unary | "(" or ")" | cond
+//	cond  = word [ ( "=" | "!=" ) word | "in" "(" word { "," word } ")" ]
+type parser struct {
+	tokens []token
+	pos    int
+	names  map[string]bool
+}
+
+// labels returns the names of the labels of the parsed conditions, sorted
+func (p *parser) labels() []string {
+	names := make([]string, 0, len(p.names))
+	for name := range p.names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+
+	return tok
+}
+
+func (p *parser) expect(kind tokenKind) (token, error) {
+	tok := p.next()
+	if tok.kind != kind {
+		return tok, fmt.Errorf("expected %s, got %s", token{kind: kind}, tok)
+	}
+
+	return tok, nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenOr {
+		p.next()
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = orNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenAnd {
+		p.next()
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = andNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	switch p.peek().kind {
+	case tokenNot:
+		p.next()
+
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return notNode{operand: operand}, nil
+	case tokenLParen:
+		p.next()
+
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err = p.expect(tokenRParen); err != nil {
+			return nil, err
+		}
+
+		return expr, nil
+	default:
+		return p.parseCond()
+	}
+}
+
+func (p *parser) parseCond() (node, error) {
+	name, err := p.expect(tokenWord)
+	if err != nil {
+		return nil, err
+	}
+
+	cond := labelNode{name: name.text}
+	if p.names == nil {
+		p.names = make(map[string]bool)
+	}
+	p.names[name.text] = true
+
+	switch tok := p.peek(); {
+	case tok.kind == tokenEq || tok.kind == tokenNotEq:
+		p.next()
+
+		value, err := p.expect(tokenWord)
+		if err != nil {
+			return nil, err
+		}
+
+		cond.values = []string{value.text}
+		if tok.kind == tokenNotEq {
+			return notNode{operand: cond}, nil
+		}
+	case tok.kind == tokenWord && tok.text == "in" && !tok.quoted:
+		p.next()
+
+		if _, err = p.expect(tokenLParen); err != nil {
+			return nil, err
+		}
+
+		for {
+			value, err := p.expect(tokenWord)
+			if err != nil {
+				return nil, err
+			}
+			cond.values = append(cond.values, value.text)
+
+			if p.peek().kind != tokenComma {
+				break
+			}
+			p.next()
+		}
+
+		if _, err = p.expect(tokenRParen); err != nil {
+			return nil, err
+		}
+	}
+
+	return cond, nil
+}
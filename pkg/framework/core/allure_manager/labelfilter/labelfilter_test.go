@@ -0,0 +1,82 @@
+package labelfilter
+
+import (
+	"testing"
+
+	"github.com/ozontech/allure-go/pkg/allure"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_Match(t *testing.T) {
+	result := allure.NewResult("test", "TestRunner/test").WithLabels(
+		allure.TagLabel("smoke"),
+		allure.TagLabel("api"),
+		allure.SeverityLabel(allure.CRITICAL),
+		allure.IDAllureLabel("42"),
+		allure.NewLabel("team", "core team"),
+	)
+
+	tests := []struct {
+		expr  string
+		match bool
+	}{
+		{"tag=smoke", true},
+		{"tag=slow", false},
+		{"!tag=slow", true},
+		{"tag!=smoke", false},
+		{"tag=smoke && severity in (critical,blocker) && !tag=slow", true},
+		{"tag=smoke && severity in (minor, trivial)", false},
+		{"tag=slow || ALLURE_ID=42", true},
+		{"!(tag=smoke || tag=slow)", false},
+		{"!tag=smoke || tag=api && severity=critical", true},
+		{"owner", false},
+		{"severity", true},
+		{`team="core team"`, true},
+		{"team='core'", false},
+	}
+
+	for _, test := range tests {
+		filter, err := Parse(test.expr)
+		require.NoError(t, err, test.expr)
+		require.Equal(t, test.match, filter.Match(result), test.expr)
+	}
+
+	var nilFilter *Filter
+	require.True(t, nilFilter.Match(result))
+}
+
+func TestFilter_CheckLabels(t *testing.T) {
+	filter, err := Parse("tag=smoke && !tag=slow || ALLURE_ID in (1, 2)")
+	require.NoError(t, err)
+	require.NoError(t, filter.CheckLabels(false))
+
+	filter, err = Parse("tag=smoke && (severity in (critical,blocker) || owner=me) && severity!=minor")
+	require.NoError(t, err)
+	require.NoError(t, filter.CheckLabels(true))
+
+	err = filter.CheckLabels(false)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "refers to owner, severity set by the test body")
+
+	var nilFilter *Filter
+	require.NoError(t, nilFilter.CheckLabels(false))
+}
+
+func TestParse_errors(t *testing.T) {
+	for expr, msg := range map[string]string{
+		"":                   `expected label name or value, got end of expression`,
+		"tag=":               `expected label name or value, got end of expression`,
+		"tag=smoke &&":       `expected label name or value, got end of expression`,
+		"(tag=smoke":         `expected ')', got end of expression`,
+		"tag=smoke)":         `unexpected ')'`,
+		"tag in smoke":       `expected '(', got "smoke"`,
+		"tag in (smoke,)":    `expected label name or value, got ')'`,
+		"tag=smoke tag=slow": `unexpected "tag"`,
+		"tag=smoke & api":    `unexpected character '&'`,
+		`tag="smoke`:         `unterminated quoted value "smoke`,
+	} {
+		_, err := Parse(expr)
+		require.Error(t, err, expr)
+		require.Contains(t, err.Error(), msg, expr)
+	}
+}
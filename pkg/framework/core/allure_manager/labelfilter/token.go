@@ -0,0 +1,122 @@
+package labelfilter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenEq
+	tokenNotEq
+	tokenNot
+	tokenAnd
+	tokenOr
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+var tokenNames = map[tokenKind]string{
+	tokenEOF:    "end of expression",
+	tokenWord:   "label name or value",
+	tokenEq:     "'='",
+	tokenNotEq:  "'!='",
+	tokenNot:    "'!'",
+	tokenAnd:    "'&&'",
+	tokenOr:     "'||'",
+	tokenLParen: "'('",
+	tokenRParen: "')'",
+	tokenComma:  "','",
+}
+
+type token struct {
+	kind   tokenKind
+	text   string
+	quoted bool
+}
+
+func (t token) String() string {
+	if t.kind == tokenWord && t.text != "" {
+		return fmt.Sprintf("%q", t.text)
+	}
+
+	return tokenNames[t.kind]
+}
+
+type operator struct {
+	text string
+	kind tokenKind
+}
+
+// operators are the operator tokens, the longer ones go first
+var operators = []operator{
+	{"&&", tokenAnd},
+	{"||", tokenOr},
+	{"!=", tokenNotEq},
+	{"!", tokenNot},
+	{"=", tokenEq},
+	{"(", tokenLParen},
+	{")", tokenRParen},
+	{",", tokenComma},
+}
+
+// tokenize splits the expression into the tokens, the last token is always tokenEOF
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+
+	rest := expr
+	for {
+		rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+		if rest == "" {
+			return append(tokens, token{kind: tokenEOF}), nil
+		}
+
+		if quote := rest[0]; quote == '"' || quote == '\'' {
+			end := strings.IndexByte(rest[1:], quote)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quoted value %s", rest)
+			}
+
+			tokens = append(tokens, token{kind: tokenWord, text: rest[1 : end+1], quoted: true})
+			rest = rest[end+2:]
+
+			continue
+		}
+
+		if op, ok := operatorPrefix(rest); ok {
+			tokens = append(tokens, token{kind: op.kind, text: op.text})
+			rest = rest[len(op.text):]
+
+			continue
+		}
+
+		end := strings.IndexFunc(rest, func(r rune) bool {
+			return unicode.IsSpace(r) || strings.ContainsRune(`"'&|!=(),`, r)
+		})
+		if end < 0 {
+			end = len(rest)
+		}
+		if end == 0 {
+			return nil, fmt.Errorf("unexpected character %q", rest[0])
+		}
+
+		tokens = append(tokens, token{kind: tokenWord, text: rest[:end]})
+		rest = rest[end:]
+	}
+}
+
+// operatorPrefix returns the operator the string starts with
+func operatorPrefix(s string) (operator, bool) {
+	for _, op := range operators {
+		if strings.HasPrefix(s, op.text) {
+			return op, true
+		}
+	}
+
+	return operator{}, false
+}
@@ -1,6 +1,10 @@
 package manager
 
-import "github.com/ozontech/allure-go/pkg/allure"
+import (
+	"io"
+
+	"github.com/ozontech/allure-go/pkg/allure"
+)
 
 // WithAttachments adds attachment to report in case of current execution context
 func (a *allureManager) WithAttachments(attachments ...*allure.Attachment) {
@@ -11,3 +15,30 @@ func (a *allureManager) WithAttachments(attachments ...*allure.Attachment) {
 func (a *allureManager) WithNewAttachment(name string, mimeType allure.MimeType, content []byte) {
 	a.ExecutionContext().AddAttachments(allure.NewAttachment(name, mimeType, content))
 }
+
+// WithNewAttachmentFromReader writes the content to the sink of current execution context and adds the attachment to report
+func (a *allureManager) WithNewAttachmentFromReader(name string, mimeType allure.MimeType, content io.Reader) error {
+	attachment, err := allure.NewAttachmentFromReaderTo(a.ExecutionContext().GetSink(), name, mimeType, content)
+	if err != nil {
+		return err
+	}
+
+	a.ExecutionContext().AddAttachments(attachment)
+	return nil
+}
+
+// WithNewAttachmentFromFile puts the file to the sink of current execution context and adds the attachment to report
+func (a *allureManager) WithNewAttachmentFromFile(name string, mimeType allure.MimeType, path string, mode allure.AttachMode) error {
+	attachment, err := allure.NewAttachmentFromFileTo(a.ExecutionContext().GetSink(), name, mimeType, path, mode)
+	if err != nil {
+		return err
+	}
+
+	a.ExecutionContext().AddAttachments(attachment)
+	return nil
+}
+
+// WithNewLazyAttachment adds attachment, whose content is produced on print, to report in case of current execution context
+func (a *allureManager) WithNewLazyAttachment(name string, mimeType allure.MimeType, producer func(w io.Writer) error) {
+	a.ExecutionContext().AddAttachments(allure.NewLazyAttachment(name, mimeType, producer))
+}
@@ -1,6 +1,10 @@
 package manager
 
 import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/ozontech/allure-go/pkg/allure"
@@ -12,6 +16,7 @@ type execMockAttach struct {
 	name   string
 	steps  []*allure.Step
 	attach []*allure.Attachment
+	sink   allure.Sink
 }
 
 func newExecMockAttach(name string) *execMockAttach {
@@ -34,6 +39,10 @@ func (m *execMockAttach) GetName() string {
 	return m.name
 }
 
+func (m *execMockAttach) GetSink() allure.Sink {
+	return m.sink
+}
+
 func TestAllureManager_Attachment(t *testing.T) {
 	mock := newExecMockAttach(constants.TestContextName)
 	attach := allure.NewAttachment("testAttach", allure.Text, []byte("test"))
@@ -55,3 +64,31 @@ func TestAllureManager_NewAttachment(t *testing.T) {
 	require.Equal(t, allure.Text, mock.attach[0].Type)
 	require.Equal(t, []byte("test"), mock.attach[0].GetContent())
 }
+
+func TestAllureManager_NewAttachmentFromFile(t *testing.T) {
+	sink := allure.NewMemorySink()
+	mock := newExecMockAttach(constants.TestContextName)
+	mock.sink = sink
+	manager := allureManager{executionContext: mock}
+
+	path := filepath.Join(t.TempDir(), "test.log")
+	require.NoError(t, os.WriteFile(path, []byte("test"), 0o644))
+
+	require.NoError(t, manager.WithNewAttachmentFromFile("testAttach", "", path, allure.CopyFile))
+	require.Len(t, mock.attach, 1)
+	require.Equal(t, "testAttach", mock.attach[0].Name)
+
+	content, ok := sink.File(mock.attach[0].Source)
+	require.True(t, ok)
+	require.Equal(t, "test", string(content))
+
+	require.NoError(t, manager.WithNewAttachmentFromReader("testReader", allure.Text, strings.NewReader("reader")))
+	require.Len(t, mock.attach, 2)
+	_, ok = sink.File(mock.attach[1].Source)
+	require.True(t, ok)
+
+	manager.WithNewLazyAttachment("testLazy", allure.Text, func(w io.Writer) error { return nil })
+	require.Len(t, mock.attach, 3)
+	_, ok = sink.File(mock.attach[2].Source)
+	require.False(t, ok)
+}
@@ -24,6 +24,12 @@ func (a *allureManager) TmsLinks(testCase ...string) {
 	a.Links(allure.TmsLinks(testCase...))
 }
 
+// LinkTo adds link of the named link type due its pattern (registered with allure.RegisterLinkType
+// or environment variable ALLURE_LINK_<TYPE>_PATTERN)
+func (a *allureManager) LinkTo(linkType string, values ...string) {
+	a.Link(allure.LinkTo(linkType, values...))
+}
+
 // Link adds Link to struct.AllureResult
 func (a *allureManager) Link(link *allure.Link) {
 	a.withResult(func(r *allure.Result) {
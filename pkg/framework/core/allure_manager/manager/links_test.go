@@ -89,3 +89,14 @@ func TestAllureManager_TmsLinks(t *testing.T) {
 	require.Equal(t, string(allure.TMS), manager.GetResult().Links[0].Type)
 	require.Equal(t, string(allure.TMS), manager.GetResult().Links[1].Type)
 }
+
+func TestAllureManager_LinkTo(t *testing.T) {
+	allure.RegisterLinkType(allure.LinkType{Name: "runbook", Pattern: "https://wiki/runbooks/{page}"})
+
+	manager := allureManager{testMeta: &testMetaMockLinks{result: &allure.Result{}}}
+	manager.LinkTo("runbook", "payments")
+	require.Len(t, manager.GetResult().Links, 1)
+	require.Equal(t, "runbook[payments]", manager.GetResult().Links[0].Name)
+	require.Equal(t, string(allure.LINK), manager.GetResult().Links[0].Type)
+	require.Equal(t, "https://wiki/runbooks/payments", manager.GetResult().Links[0].URL)
+}
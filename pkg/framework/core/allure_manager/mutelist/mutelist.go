@@ -0,0 +1,236 @@
+package mutelist
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/ozontech/allure-go/pkg/allure"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	once     = sync.Once{}
+	muteList = initMuteList()
+)
+
+// Path to the mute list, `muteListPath` of the allure-go config file if not set
+const muteListPath = "ALLURE_MUTE_LIST_PATH"
+
+// expiresLayout is the layout of Entry.Expires
+const expiresLayout = "2006-01-02"
+
+// now returns the current time, it is replaced in tests
+var now = time.Now
+
+// Entry is the muted test or group of tests
+type Entry struct {
+	Selector string `json:"selector,omitempty" yaml:"selector,omitempty"` // Full name of the test or glob pattern of the full names (e.g. `TestRunner/MySuite/*`)
+	AllureID string `json:"allureId,omitempty" yaml:"allureId,omitempty"` // Value of the `ALLURE_ID` label of the test
+	Reason   string `json:"reason,omitempty" yaml:"reason,omitempty"`     // Why the test is muted
+	Issue    string `json:"issue,omitempty" yaml:"issue,omitempty"`       // Ticket of the mute: ID (see `$ALLURE_ISSUE_PATTERN`) or URL
+	Expires  string `json:"expires,omitempty" yaml:"expires,omitempty"`   // Date (`YYYY-MM-DD`) after which the entry is ignored
+
+	expires time.Time
+}
+
+// MuteList is the list of the muted tests: they run, but their failures are muted (see provider.T.Muted)
+type MuteList struct {
+	Tests []*Entry `json:"tests" yaml:"tests"`
+}
+
+// Load reads the mute list file. The format is chosen by the extension: `.json` or YAML otherwise.
+// The relative path is looked up from the working directory up to the directory with `go.mod`.
+// The expired entries are reported and ignored.
+func Load(filePath string) (*MuteList, error) {
+	raw, err := findMuteList(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var list MuteList
+
+	if strings.EqualFold(filepath.Ext(filePath), ".json") {
+		err = json.Unmarshal(raw, &list)
+	} else {
+		err = yaml.Unmarshal(raw, &list)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse mute list %s: %w", filePath, err)
+	}
+
+	tests := make([]*Entry, 0, len(list.Tests))
+
+	for _, entry := range list.Tests {
+		if entry == nil || (entry.Selector == "" && entry.AllureID == "") {
+			continue
+		}
+
+		if entry.Expires != "" {
+			if entry.expires, err = time.Parse(expiresLayout, entry.Expires); err != nil {
+				return nil, fmt.Errorf("wrong expiry date of %s in mute list %s: %w", entry, filePath, err)
+			}
+
+			// the entry is valid until the end of its expiry date
+			if !now().Before(entry.expires.AddDate(0, 0, 1)) {
+				fmt.Printf("Mute list entry %s expired on %s, it is ignored. Please, fix the test or prolong the mute.\n", entry, entry.Expires)
+				continue
+			}
+		}
+
+		tests = append(tests, entry)
+	}
+
+	list.Tests = tests
+
+	return &list, nil
+}
+
+// Match returns the first entry matching the result and true if it is found.
+// The entry matches by the `ALLURE_ID` label or by the full name of the result.
+func (l *MuteList) Match(result *allure.Result) (*Entry, bool) {
+	if l == nil || result == nil {
+		return nil, false
+	}
+
+	var allureID string
+	if label, ok := result.GetFirstLabel(allure.AllureID); ok {
+		allureID = label.GetValue()
+	}
+
+	for _, entry := range l.Tests {
+		if entry.AllureID != "" && entry.AllureID == allureID {
+			return entry, true
+		}
+
+		if entry.Selector == "" {
+			continue
+		}
+
+		if entry.Selector == result.FullName {
+			return entry, true
+		}
+
+		if matched, err := path.Match(entry.Selector, result.FullName); err == nil && matched {
+			return entry, true
+		}
+	}
+
+	return nil, false
+}
+
+// Apply mutes the test of the result if it matches the list: mute is called with the reason of the entry
+// (provider.T.Muted), the result gets the link to the issue of the entry. Returns true if the test is muted.
+func (l *MuteList) Apply(result *allure.Result, mute func(reason string)) bool {
+	entry, ok := l.Match(result)
+	if !ok {
+		return false
+	}
+
+	if entry.Issue != "" {
+		result.Links = append(result.Links, entry.link())
+	}
+
+	mute(entry.reason())
+
+	return true
+}
+
+// String returns the selector or the `ALLURE_ID` of the entry
+func (e *Entry) String() string {
+	if e.Selector != "" {
+		return e.Selector
+	}
+
+	return "ALLURE_ID " + e.AllureID
+}
+
+func (e *Entry) reason() string {
+	switch {
+	case e.Issue == "":
+		return e.Reason
+	case e.Reason == "":
+		return e.Issue
+	default:
+		return fmt.Sprintf("%s (%s)", e.Reason, e.Issue)
+	}
+}
+
+func (e *Entry) link() *allure.Link {
+	if strings.HasPrefix(e.Issue, "http://") || strings.HasPrefix(e.Issue, "https://") {
+		return allure.NewLink(e.Issue, allure.ISSUE, e.Issue)
+	}
+
+	return allure.IssueLink(e.Issue)
+}
+
+func newMuteList() (*MuteList, error) {
+	filePath := allure.GetConfig().MuteListPath
+	if filePath == "" {
+		return nil, fmt.Errorf("{%s} environment variable not set", muteListPath)
+	}
+
+	return Load(filePath)
+}
+
+func initMuteList() *MuteList {
+	var (
+		err   error
+		mList *MuteList
+	)
+
+	muteListOnce := func() {
+		mList, err = newMuteList()
+		if err == nil {
+			fmt.Printf("Mute list found: %d tests are muted\n", len(mList.Tests))
+		} else if allure.GetConfig().MuteListPath != "" {
+			fmt.Printf("Mute list is ignored: %s\n", err)
+		}
+	}
+	once.Do(muteListOnce)
+
+	return mList
+}
+
+// GetMuteList returns the mute list of the process, nil if there is no mute list
+func GetMuteList() *MuteList {
+	return muteList
+}
+
+// findMuteList reads the file by the path. The relative path is looked up in the working directory
+// and its parents up to the directory with `go.mod`.
+func findMuteList(filePath string) ([]byte, error) {
+	raw, err := os.ReadFile(filepath.Clean(filePath))
+	if err == nil || filepath.IsAbs(filePath) {
+		return raw, err
+	}
+
+	dir, wdErr := os.Getwd()
+	if wdErr != nil {
+		return nil, wdErr
+	}
+
+	for {
+		if raw, err = os.ReadFile(filepath.Join(dir, filepath.Clean(filePath))); err == nil {
+			return raw, nil
+		}
+
+		// stop looking if project root found
+		if _, statErr := os.Stat(filepath.Join(dir, "go.mod")); statErr == nil {
+			return nil, err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, err
+		}
+
+		dir = parent
+	}
+}
@@ -0,0 +1,108 @@
+package mutelist
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ozontech/allure-go/pkg/allure"
+	"github.com/stretchr/testify/require"
+)
+
+const testMuteListYAML = `
+tests:
+  - selector: TestRunner/Suite/TestFlaky
+    reason: flaky on CI
+    issue: JIRA-1
+  - selector: TestRunner/Legacy/*
+    issue: https://tracker/JIRA-2
+  - allureId: "42"
+    reason: known bug
+    expires: 2026-10-20
+  - selector: TestRunner/Suite/TestExpired
+    expires: 2026-10-01
+  - reason: no selector
+`
+
+func setNow(t *testing.T, date string) {
+	t.Helper()
+
+	parsed, err := time.Parse(expiresLayout, date)
+	require.NoError(t, err)
+
+	now = func() time.Time { return parsed.Add(12 * time.Hour) }
+	t.Cleanup(func() { now = time.Now })
+}
+
+func TestLoad(t *testing.T) {
+	setNow(t, "2026-10-20")
+
+	path := filepath.Join(t.TempDir(), "mute.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testMuteListYAML), 0o644))
+
+	list, err := Load(path)
+	require.NoError(t, err)
+	require.Len(t, list.Tests, 3)
+	require.Equal(t, "TestRunner/Suite/TestFlaky", list.Tests[0].Selector)
+	require.Equal(t, "42", list.Tests[2].AllureID)
+
+	setNow(t, "2026-10-21")
+	list, err = Load(path)
+	require.NoError(t, err)
+	require.Len(t, list.Tests, 2)
+
+	jsonPath := filepath.Join(t.TempDir(), "mute.json")
+	require.NoError(t, os.WriteFile(jsonPath, []byte(`{"tests":[{"selector":"TestA","expires":"tomorrow"}]}`), 0o644))
+	_, err = Load(jsonPath)
+	require.Error(t, err)
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.Error(t, err)
+}
+
+func TestMuteList_Match(t *testing.T) {
+	list := &MuteList{Tests: []*Entry{
+		{Selector: "TestRunner/Suite/TestFlaky"},
+		{Selector: "TestRunner/Legacy/*"},
+		{AllureID: "42"},
+	}}
+
+	entry, ok := list.Match(allure.NewResult("TestFlaky", "TestRunner/Suite/TestFlaky"))
+	require.True(t, ok)
+	require.Equal(t, list.Tests[0], entry)
+
+	entry, ok = list.Match(allure.NewResult("TestOld", "TestRunner/Legacy/TestOld"))
+	require.True(t, ok)
+	require.Equal(t, list.Tests[1], entry)
+
+	withID := allure.NewResult("TestRenamed", "TestRunner/Suite/TestRenamed")
+	withID.AddLabel(allure.IDAllureLabel("42"))
+	entry, ok = list.Match(withID)
+	require.True(t, ok)
+	require.Equal(t, list.Tests[2], entry)
+
+	_, ok = list.Match(allure.NewResult("TestOther", "TestRunner/Suite/TestOther"))
+	require.False(t, ok)
+
+	var noList *MuteList
+	_, ok = noList.Match(withID)
+	require.False(t, ok)
+}
+
+func TestMuteList_Apply(t *testing.T) {
+	list := &MuteList{Tests: []*Entry{
+		{Selector: "TestRunner/Suite/TestFlaky", Reason: "flaky on CI", Issue: "https://tracker/JIRA-1"},
+	}}
+
+	var reason string
+
+	result := allure.NewResult("TestFlaky", "TestRunner/Suite/TestFlaky")
+	require.True(t, list.Apply(result, func(r string) { reason = r }))
+	require.Equal(t, "flaky on CI (https://tracker/JIRA-1)", reason)
+	require.Len(t, result.Links, 1)
+	require.Equal(t, string(allure.ISSUE), result.Links[0].Type)
+	require.Equal(t, "https://tracker/JIRA-1", result.Links[0].URL)
+
+	require.False(t, list.Apply(allure.NewResult("TestOther", "TestOther"), func(string) { t.Fatal("not muted test is muted") }))
+}
@@ -3,11 +3,17 @@ package testplan
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/goccy/go-json"
+	"github.com/ozontech/allure-go/pkg/allure"
+	"gopkg.in/yaml.v3"
 )
 
 var (
@@ -15,58 +21,256 @@ var (
 	testPlan = initTestPlan()
 )
 
-// Path to testplan.json
+// Path to the test plan file, `testPlanPath` of the allure-go config file if not set
 const testPlanPath = "ALLURE_TESTPLAN_PATH"
 
-type TestCase struct {
-	ID       int    `json:"id"`
-	Selector string `json:"selector"`
-}
+// Inline test plan, `testPlan` of the allure-go config file if not set
+const inlineTestPlan = "ALLURE_TESTPLAN"
 
-type TestPlan struct {
-	Version string      `json:"version"`
-	Tests   []*TestCase `json:"tests"`
+// regexpPrefix is the prefix of the selector matching the full names by the regular expression
+const regexpPrefix = "regexp:"
+
+// AllureID is the ID of the test case in Allure TestOps, it is written as a string or as a number.
+// Use AllureIDOf to convert the numeric ID.
+type AllureID string
+
+// AllureIDOf returns the AllureID of the numeric ID
+func AllureIDOf(id int) AllureID {
+	return AllureID(strconv.Itoa(id))
 }
 
-func newTestPlan() (*TestPlan, error) {
-	filePath := os.Getenv(testPlanPath)
-	if filePath == "" {
-		return nil, fmt.Errorf("{%s} environment variable not set", testPlanPath)
+// UnmarshalJSON reads the ID written as a string or as a number
+func (id *AllureID) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
 	}
 
-	if filepath.Ext(filePath) != ".json" {
-		return nil, fmt.Errorf("%s environment variable has a wrong format. Please, set path to .json file. Current path:%s", testPlanPath, filePath)
+	switch v := value.(type) {
+	case nil:
+		*id = ""
+	case string:
+		*id = AllureID(v)
+	case float64:
+		*id = AllureID(strconv.FormatFloat(v, 'f', -1, 64))
+	default:
+		return fmt.Errorf("test case id must be a string or a number, got %s", data)
 	}
 
+	return nil
+}
+
+// TestCase is the test selected by the test plan.
+// It matches the test by the `ALLURE_ID` (or `as_id`) label of the result or by the selector.
+type TestCase struct {
+	ID       AllureID `json:"id,omitempty" yaml:"id,omitempty"`             // Value of the `ALLURE_ID` label of the test
+	Selector string   `json:"selector,omitempty" yaml:"selector,omitempty"` // Full name of the test, its glob pattern (e.g. `TestRunner/MySuite/*`) or `regexp:<expression>`
+
+	re       *regexp.Regexp
+	matched  int32
+	reported int32
+}
+
+type TestPlan struct {
+	Version string      `json:"version" yaml:"version"`
+	Tests   []*TestCase `json:"tests" yaml:"tests"`
+}
+
+// Load reads the test plan file. The format is chosen by the extension: `.json` or YAML otherwise.
+// The relative path is looked up from the working directory up to the directory with `go.mod`.
+func Load(filePath string) (*TestPlan, error) {
 	testPlanRaw, err := findTestPlan(filePath)
 	if err != nil {
 		return nil, err
 	}
 
-	var plan TestPlan
+	return parse(testPlanRaw, strings.EqualFold(filepath.Ext(filePath), ".json"), filePath)
+}
+
+// Parse parses the content of the test plan: JSON if it starts with `{`, YAML otherwise
+func Parse(raw string) (*TestPlan, error) {
+	return parse([]byte(raw), strings.HasPrefix(strings.TrimSpace(raw), "{"), "inline test plan")
+}
+
+// parse parses the test plan, the invalid entries are reported and ignored
+func parse(raw []byte, isJSON bool, source string) (*TestPlan, error) {
+	var (
+		plan TestPlan
+		err  error
+	)
+
+	if isJSON {
+		err = json.Unmarshal(raw, &plan)
+	} else {
+		err = yaml.Unmarshal(raw, &plan)
+	}
 
-	err = json.Unmarshal(testPlanRaw, &plan)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to parse test plan %s: %w", source, err)
 	}
 
-	if len(plan.Tests) == 0 {
-		return nil, fmt.Errorf("no any tests found in %s", filePath)
+	tests := make([]*TestCase, 0, len(plan.Tests))
+
+	for _, tc := range plan.Tests {
+		if tc == nil || (tc.ID == "" && tc.Selector == "") {
+			fmt.Printf("Test plan entry without id and selector is ignored (%s)\n", source)
+			continue
+		}
+
+		if strings.HasPrefix(tc.Selector, regexpPrefix) {
+			if tc.re, err = regexp.Compile("^(?:" + strings.TrimPrefix(tc.Selector, regexpPrefix) + ")$"); err != nil {
+				fmt.Printf("Test plan entry %s is ignored: invalid regular expression: %s\n", tc, err)
+				continue
+			}
+		}
+
+		tests = append(tests, tc)
 	}
 
+	if len(tests) == 0 {
+		return nil, fmt.Errorf("no any tests found in %s", source)
+	}
+
+	plan.Tests = tests
+
 	return &plan, nil
 }
 
-// IsSelected returns true if selector matches with testplan selector
-// TODO: ID parsing from TestOps
+// IsSelected returns true if the test with the `ALLURE_ID` id or the full name selector is selected by the test plan
 func (p *TestPlan) IsSelected(id, selector string) bool {
-	for _, t := range p.Tests {
-		if t.Selector == selector {
-			return true
+	selected := false
+
+	for _, tc := range p.Tests {
+		if tc.match(id, selector) {
+			atomic.StoreInt32(&tc.matched, 1)
+			selected = true
+		}
+	}
+
+	return selected
+}
+
+// IsResultSelected returns true if the test of the result is selected by the test plan.
+// The test is matched by its `ALLURE_ID` (or `as_id`) label and by its full name.
+func (p *TestPlan) IsResultSelected(result *allure.Result) bool {
+	return p.IsSelected(allureIDOf(result), result.FullName)
+}
+
+// Unmatched returns the entries of the test plan matched no test of the process so far
+func (p *TestPlan) Unmatched() []*TestCase {
+	var unmatched []*TestCase
+
+	for _, tc := range p.Tests {
+		if atomic.LoadInt32(&tc.matched) == 0 {
+			unmatched = append(unmatched, tc)
+		}
+	}
+
+	return unmatched
+}
+
+// PrintUnmatched prints the entries of the test plan matched no test of the process and not printed yet.
+// It is called after all tests are run (e.g. in TestMain), the tests of the other packages are not taken into account.
+func (p *TestPlan) PrintUnmatched() {
+	if p == nil {
+		return
+	}
+
+	for _, tc := range p.Unmatched() {
+		tc.report()
+	}
+}
+
+// PrintUnmatchedOf prints the entries of the test plan that can select only the tests of the top-level test
+// and matched none of them. The runner calls it when the top-level test is finished.
+// The entries with ALLURE_ID or with the regular expression can select any test, they are printed by PrintUnmatched.
+func (p *TestPlan) PrintUnmatchedOf(topLevelName string) {
+	if p == nil {
+		return
+	}
+
+	for _, tc := range p.Unmatched() {
+		if tc.ID != "" || tc.re != nil {
+			continue
 		}
+
+		scope := strings.SplitN(tc.Selector, "/", 2)[0]
+		if matched, err := path.Match(scope, topLevelName); err == nil && matched {
+			tc.report()
+		}
+	}
+}
+
+// report prints the entry matched no test, once per process
+func (tc *TestCase) report() {
+	if !atomic.CompareAndSwapInt32(&tc.reported, 0, 1) {
+		return
+	}
+
+	if tc.Selector == "" {
+		fmt.Printf("Test plan entry %s matched no test: the ALLURE_ID must be known before the test is run, "+
+			"set it with GetAllureID(testName) of the suite (AllureIDSuite)\n", tc)
+		return
+	}
+
+	fmt.Printf("Test plan entry %s matched no test: the selector is compared with the full name of the test, e.g. TestRunner/MySuite/TestName\n", tc)
+}
+
+// String returns the selector and the ID of the entry
+func (tc *TestCase) String() string {
+	switch {
+	case tc.ID == "":
+		return tc.Selector
+	case tc.Selector == "":
+		return "ALLURE_ID " + string(tc.ID)
+	default:
+		return fmt.Sprintf("%s (ALLURE_ID %s)", tc.Selector, tc.ID)
+	}
+}
+
+// match returns true if the entry matches the ID or the full name of the test
+func (tc *TestCase) match(id, fullName string) bool {
+	if tc.ID != "" && string(tc.ID) == id {
+		return true
+	}
+
+	switch {
+	case tc.Selector == "":
+		return false
+	case tc.Selector == fullName:
+		return true
+	case tc.re != nil:
+		return tc.re.MatchString(fullName)
+	default:
+		matched, err := path.Match(tc.Selector, fullName)
+		return err == nil && matched
+	}
+}
+
+// allureIDOf returns the value of the `ALLURE_ID` label of the result, `as_id` label if it is not set
+func allureIDOf(result *allure.Result) string {
+	if label, ok := result.GetFirstLabel(allure.AllureID); ok {
+		return label.GetValue()
+	}
+
+	if label, ok := result.GetFirstLabel(allure.ID); ok {
+		return label.GetValue()
+	}
+
+	return ""
+}
+
+func newTestPlan() (*TestPlan, error) {
+	if inline := allure.GetConfig().TestPlan; strings.TrimSpace(inline) != "" {
+		return Parse(inline)
+	}
+
+	filePath := allure.GetConfig().TestPlanPath
+	if filePath == "" {
+		return nil, fmt.Errorf("{%s} or {%s} environment variable not set", inlineTestPlan, testPlanPath)
 	}
 
-	return false
+	return Load(filePath)
 }
 
 func initTestPlan() *TestPlan {
@@ -78,7 +282,9 @@ func initTestPlan() *TestPlan {
 	testPlanOnce := func() {
 		tPlan, err = newTestPlan()
 		if err == nil {
-			fmt.Printf("TestPlan found!")
+			fmt.Printf("Test plan found: %d tests are selected\n", len(tPlan.Tests))
+		} else if allure.GetConfig().TestPlanPath != "" || allure.GetConfig().TestPlan != "" {
+			fmt.Printf("Test plan is ignored: %s\n", err)
 		}
 	}
 	once.Do(testPlanOnce)
@@ -86,7 +292,7 @@ func initTestPlan() *TestPlan {
 	return tPlan
 }
 
-// GetTestPlan ...
+// GetTestPlan returns the test plan of the process, nil if there is no test plan
 func GetTestPlan() *TestPlan {
 	return testPlan
 }
@@ -0,0 +1,109 @@
+package testplan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ozontech/allure-go/pkg/allure"
+	"github.com/stretchr/testify/require"
+)
+
+const testPlanJSON = `{
+  "version": "1.0",
+  "tests": [
+    {"id": "1042"},
+    {"id": 1043, "selector": "TestRunner/Suite/TestRenamed"},
+    {"selector": "TestRunner/Suite/TestExact"}
+  ]
+}`
+
+const testPlanYAML = `
+version: "1.0"
+tests:
+  - id: 1042
+  - selector: TestRunner/Legacy/*
+  - selector: regexp:TestRunner/Suite/Test(Login|Logout)
+  - selector: regexp:TestRunner/(
+  - {}
+`
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	jsonPath := filepath.Join(dir, "testplan.json")
+	require.NoError(t, os.WriteFile(jsonPath, []byte(testPlanJSON), 0o644))
+
+	plan, err := Load(jsonPath)
+	require.NoError(t, err)
+	require.Equal(t, "1.0", plan.Version)
+	require.Len(t, plan.Tests, 3)
+	require.Equal(t, AllureID("1042"), plan.Tests[0].ID)
+	require.Equal(t, AllureID("1043"), plan.Tests[1].ID)
+
+	yamlPath := filepath.Join(dir, "testplan.yaml")
+	require.NoError(t, os.WriteFile(yamlPath, []byte(testPlanYAML), 0o644))
+
+	plan, err = Load(yamlPath)
+	require.NoError(t, err)
+	// the invalid regular expression and the empty entry are ignored
+	require.Len(t, plan.Tests, 3)
+	require.Equal(t, AllureID("1042"), plan.Tests[0].ID)
+
+	require.NoError(t, os.WriteFile(jsonPath, []byte(`{"tests": [{"id": true}]}`), 0o644))
+	_, err = Load(jsonPath)
+	require.Error(t, err)
+
+	_, err = Parse("tests: []")
+	require.Error(t, err)
+}
+
+func TestTestPlan_IsResultSelected(t *testing.T) {
+	plan, err := Parse(testPlanYAML)
+	require.NoError(t, err)
+
+	newResult := func(fullName string, labels ...*allure.Label) *allure.Result {
+		return allure.NewResult("test", fullName).WithLabels(labels...)
+	}
+
+	require.True(t, plan.IsResultSelected(newResult("TestRunner/Other/TestA", allure.IDAllureLabel("1042"))))
+	require.True(t, plan.IsResultSelected(newResult("TestRunner/Other/TestB", allure.IDLabel("1042"))))
+	require.False(t, plan.IsResultSelected(newResult("TestRunner/Other/TestC", allure.IDAllureLabel("1043"))))
+	require.True(t, plan.IsResultSelected(newResult("TestRunner/Legacy/TestD")))
+	require.False(t, plan.IsResultSelected(newResult("TestRunner/Legacy/Nested/TestE")))
+	require.True(t, plan.IsResultSelected(newResult("TestRunner/Suite/TestLogin")))
+	require.False(t, plan.IsResultSelected(newResult("TestRunner/Suite/TestLogin2")))
+
+	plan, err = Parse(testPlanJSON)
+	require.NoError(t, err)
+	require.True(t, plan.IsResultSelected(newResult("TestRunner/Suite/TestExact")))
+	require.True(t, plan.IsSelected("1043", "TestRunner/Suite/TestOther"))
+	require.False(t, plan.IsSelected("", "TestRunner/Suite/TestOther"))
+
+	unmatched := plan.Unmatched()
+	require.Len(t, unmatched, 1)
+	require.Equal(t, "ALLURE_ID 1042", unmatched[0].String())
+}
+
+func TestTestPlan_PrintUnmatchedOf(t *testing.T) {
+	plan, err := Parse(testPlanYAML)
+	require.NoError(t, err)
+
+	plan.PrintUnmatchedOf("TestOther")
+	for _, tc := range plan.Tests {
+		require.Zero(t, tc.reported, tc.String())
+	}
+
+	// only the glob selector is scoped to the top-level test, the ID and the regular expression can match any test
+	plan.PrintUnmatchedOf("TestRunner")
+	require.Zero(t, plan.Tests[0].reported)
+	require.Equal(t, int32(1), plan.Tests[1].reported)
+	require.Zero(t, plan.Tests[2].reported)
+
+	plan.PrintUnmatched()
+	for _, tc := range plan.Tests {
+		require.Equal(t, int32(1), tc.reported, tc.String())
+	}
+
+	require.Equal(t, AllureID("1042"), AllureIDOf(1042))
+}
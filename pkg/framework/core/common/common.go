@@ -1,16 +1,20 @@
 package common
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"runtime/debug"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/ozontech/allure-go/pkg/allure"
 	"github.com/ozontech/allure-go/pkg/framework/asserts_wrapper/helper"
+	"github.com/ozontech/allure-go/pkg/framework/core/allure_manager/labelfilter"
 	"github.com/ozontech/allure-go/pkg/framework/core/allure_manager/manager"
+	"github.com/ozontech/allure-go/pkg/framework/core/allure_manager/mutelist"
 	"github.com/ozontech/allure-go/pkg/framework/core/allure_manager/testplan"
 	"github.com/ozontech/allure-go/pkg/framework/core/constants"
 	"github.com/ozontech/allure-go/pkg/framework/provider"
@@ -27,6 +31,17 @@ type Common struct {
 
 	xSkip bool
 
+	mutedMu     sync.RWMutex
+	muted       bool
+	mutedReason string
+
+	timeoutMu      sync.Mutex
+	timeoutHandler func(timeout time.Duration)
+
+	ctxMu  sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+
 	wg sync.WaitGroup
 
 	tempDirMu  sync.Mutex
@@ -45,6 +60,7 @@ func NewT(realT provider.TestingT) *Common {
 
 func (c *Common) registerError(fullMessage string) {
 	xSkipPrefix := "[XSkip]"
+	c.CancelContext()
 	res := c.GetResult()
 
 	if res != nil && res.Status != allure.Broken {
@@ -57,10 +73,22 @@ func (c *Common) registerError(fullMessage string) {
 
 	if res != nil {
 		res.StatusDetails.Message = extractErrorMessages(fullMessage)
+		if muted, reason := c.isMuted(); muted && reason != "" {
+			res.StatusDetails.Message = fmt.Sprintf("[Muted: %s] %s", reason, res.StatusDetails.Message)
+		}
 		res.StatusDetails.Trace = fmt.Sprintf("%s\n%s", res.StatusDetails.Trace, fullMessage)
 	}
 }
 
+// MaskedValues returns the values of the masked parameters of the test, the assertions of the test mask them
+func (c *Common) MaskedValues() []string {
+	if r := c.GetResult(); r != nil {
+		return r.MaskedValues()
+	}
+
+	return nil
+}
+
 func (c *Common) withResult(f func(result *allure.Result)) {
 	if r := c.GetResult(); r != nil {
 		f(r)
@@ -96,6 +124,119 @@ func (c *Common) XSkip() {
 	c.xSkip = true
 }
 
+// Flaky marks current test as unstable in the report
+func (c *Common) Flaky() {
+	c.withResult(func(r *allure.Result) {
+		r.StatusDetails.Flaky = true
+	})
+}
+
+// Known marks the failure of current test as a known issue in the report
+func (c *Common) Known() {
+	c.withResult(func(r *allure.Result) {
+		r.StatusDetails.Known = true
+	})
+}
+
+// Muted marks current test as muted: its failures are recorded in the report, but don't fail the run.
+// The test stopped by a failure (e.g. by Require assertion) is skipped in the run.
+func (c *Common) Muted(reason string) {
+	c.mutedMu.Lock()
+	c.muted = true
+	c.mutedReason = reason
+	c.mutedMu.Unlock()
+
+	c.withResult(func(r *allure.Result) {
+		r.StatusDetails.Muted = true
+	})
+}
+
+func (c *Common) isMuted() (bool, string) {
+	c.mutedMu.RLock()
+	defer c.mutedMu.RUnlock()
+
+	return c.muted, c.mutedReason
+}
+
+// WithTimeout sets the timeout of current test counted from its start. The test exceeding the timeout is marked broken
+// with the goroutine dump attached, its after each hook is run and its result is printed.
+// The test run without the timeout at its start is finalized when its body returns, the timeout cancels its context.
+// Zero timeout disables the timeout of the test.
+func (c *Common) WithTimeout(timeout time.Duration) {
+	c.timeoutMu.Lock()
+	handler := c.timeoutHandler
+	c.timeoutMu.Unlock()
+
+	if handler == nil {
+		c.Logf("WithTimeout is ignored: the test is not run by the allure-go runner")
+		return
+	}
+
+	handler(timeout)
+}
+
+// SetTimeoutHandler sets the function changing the timeout of current test, it is set by the runner
+func (c *Common) SetTimeoutHandler(handler func(timeout time.Duration)) {
+	c.timeoutMu.Lock()
+	defer c.timeoutMu.Unlock()
+
+	c.timeoutHandler = handler
+}
+
+// Context returns the context of current test. It is cancelled when the test finishes or fails.
+func (c *Common) Context() context.Context {
+	c.ctxMu.Lock()
+	defer c.ctxMu.Unlock()
+
+	if c.ctx == nil {
+		c.ctx, c.cancel = context.WithCancel(context.Background())
+	}
+
+	return c.ctx
+}
+
+// CancelContext cancels the context of current test, it is called by the runner when the test finishes
+func (c *Common) CancelContext() {
+	c.ctxMu.Lock()
+	defer c.ctxMu.Unlock()
+
+	if c.ctx == nil {
+		c.ctx, c.cancel = context.WithCancel(context.Background())
+	}
+
+	c.cancel()
+}
+
+// inheritContext makes the context of current test a child of the parent test context
+func (c *Common) inheritContext(parent *Common) {
+	parentCtx := parent.Context()
+
+	c.ctxMu.Lock()
+	defer c.ctxMu.Unlock()
+
+	c.ctx, c.cancel = context.WithCancel(parentCtx)
+}
+
+// failTest fails the test in the run unless it is muted
+func (c *Common) failTest() {
+	c.CancelContext()
+	if muted, _ := c.isMuted(); muted {
+		return
+	}
+
+	c.TestingT.Fail()
+}
+
+// failTestNow fails the test in the run and stops it. The muted test is skipped instead.
+func (c *Common) failTestNow() {
+	c.CancelContext()
+	if muted, reason := c.isMuted(); muted {
+		c.TestingT.Skipf("Muted: %s", reason)
+	}
+
+	c.TestingT.FailNow()
+}
+
 // GetProvider ...
 func (c *Common) GetProvider() provider.Provider {
 	return c.Provider
@@ -124,6 +265,10 @@ func (c *Common) Error(args ...interface{}) {
 
 	fullMessage := fmt.Sprint(args...)
 	c.registerError(fullMessage)
+	if muted, _ := c.isMuted(); muted {
+		c.TestingT.Log(args...)
+		return
+	}
 	c.TestingT.Error(args...)
 }
 
@@ -133,6 +278,10 @@ func (c *Common) Errorf(format string, args ...interface{}) {
 
 	fullMessage := fmt.Sprintf(format, args...)
 	c.registerError(fullMessage)
+	if muted, _ := c.isMuted(); muted {
+		c.TestingT.Logf(format, args...)
+		return
+	}
 	c.TestingT.Errorf(format, args...)
 }
 
@@ -142,6 +291,10 @@ func (c *Common) Fatal(args ...interface{}) {
 
 	fullMessage := fmt.Sprintf("%s", args...)
 	c.registerError(fullMessage)
+	if muted, _ := c.isMuted(); muted {
+		c.TestingT.Log(args...)
+		c.failTestNow()
+	}
 	c.TestingT.Fatal(args...)
 }
 
@@ -151,6 +304,10 @@ func (c *Common) Fatalf(format string, args ...interface{}) {
 
 	fullMessage := fmt.Sprintf(format, args...)
 	c.registerError(fullMessage)
+	if muted, _ := c.isMuted(); muted {
+		c.TestingT.Logf(format, args...)
+		c.failTestNow()
+	}
 	c.TestingT.Fatalf(format, args...)
 }
 
@@ -188,7 +345,7 @@ func (c *Common) Name() string {
 // Fail ...
 func (c *Common) Fail() {
 	c.GetProvider().GetResult().Status = allure.Failed
-	c.TestingT.Fail()
+	c.failTest()
 }
 
 // FailNow ...
@@ -198,7 +355,7 @@ func (c *Common) FailNow() {
 			r.Status = allure.Failed
 		}
 	})
-	c.TestingT.FailNow()
+	c.failTestNow()
 }
 
 // Broken ...
@@ -206,7 +363,7 @@ func (c *Common) Broken() {
 	c.withResult(func(r *allure.Result) {
 		r.Status = allure.Broken
 	})
-	c.TestingT.Fail()
+	c.failTest()
 }
 
 // BrokenNow ...
@@ -215,7 +372,7 @@ func (c *Common) BrokenNow() {
 		r.Status = allure.Broken
 	})
 
-	c.TestingT.FailNow()
+	c.failTestNow()
 }
 
 // Skip ...
@@ -332,13 +489,26 @@ func (c *Common) Run(testName string, testBody func(provider.T), tags ...string)
 
 		newProvider.NewTest(testName, packageName, tags...)
 		if testPlan := testplan.GetTestPlan(); testPlan != nil {
-			if !testPlan.IsSelected(newProvider.GetTestMeta().GetResult().TestCaseID, newProvider.GetResult().FullName) {
+			if !testPlan.IsResultSelected(newProvider.GetResult()) {
 				realT.Skip("Test is not Selected in Test Plan")
 			}
 		}
+		// the nested tests are selected together with their parent test
+		if c.Provider.GetTestMeta().GetResult() == nil {
+			filter := labelfilter.GetLabelFilter()
+			if err := filter.CheckLabels(false); err != nil {
+				realT.Fatalf("allure-go: %s", err)
+			}
+			if !filter.Match(newProvider.GetResult()) {
+				realT.Skip("Test is not selected by the label filter")
+			}
+		}
 		newProvider.TestContext()
 
 		testT.SetProvider(newProvider)
+		testT.inheritContext(c)
+		defer testT.CancelContext()
+		mutelist.GetMuteList().Apply(newProvider.GetResult(), testT.Muted)
 
 		defer func() {
 			res = testT.GetResult()
@@ -1,11 +1,13 @@
 package common
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/goccy/go-json"
 	"github.com/ozontech/allure-go/pkg/allure"
@@ -34,6 +36,10 @@ func (m *executionContextCommMock) AddStep(step *allure.Step) {
 	m.steps = append(m.steps, step)
 }
 
+func (m *executionContextCommMock) GetSink() allure.Sink {
+	return nil
+}
+
 func (m *executionContextCommMock) AddAttachments(attachments ...*allure.Attachment) {
 	m.attachments = append(m.attachments, attachments...)
 }
@@ -184,6 +190,8 @@ type commonTMock struct {
 	parallel   bool
 	run        bool
 	skipped    bool
+	failed     bool
+	logs       []string
 	TestingT   *testing.T
 }
 
@@ -195,6 +203,22 @@ func (m *commonTMock) Skip(args ...interface{}) {
 	m.skipped = true
 }
 
+func (m *commonTMock) Skipf(format string, args ...interface{}) {
+	m.skipped = true
+}
+
+func (m *commonTMock) Fail() {
+	m.failed = true
+}
+
+func (m *commonTMock) Log(args ...interface{}) {
+	m.logs = append(m.logs, fmt.Sprint(args...))
+}
+
+func (m *commonTMock) Logf(format string, args ...interface{}) {
+	m.logs = append(m.logs, fmt.Sprintf(format, args...))
+}
+
 func (m *commonTMock) Step(step *allure.Step) {
 	m.steps = append(m.steps, step)
 }
@@ -371,6 +395,69 @@ func TestCommon_Skip(t *testing.T) {
 	require.True(t, mockT.skipped)
 }
 
+func TestCommon_Muted(t *testing.T) {
+	mockT := newCommonTMock()
+	comm := Common{TestingT: mockT, Provider: newProviderMockCommon("name", "fullName")}
+
+	comm.Muted("JIRA-1")
+	comm.Errorf("expected %d", 1)
+	comm.Fail()
+	comm.Broken()
+
+	result := comm.GetResult()
+	require.True(t, result.StatusDetails.Muted)
+	require.Equal(t, allure.Broken, result.Status)
+	require.Equal(t, "[Muted: JIRA-1] expected 1", result.StatusDetails.Message)
+	require.False(t, mockT.errorfFlag)
+	require.False(t, mockT.failed)
+	require.Equal(t, []string{"expected 1"}, mockT.logs)
+
+	comm.FailNow()
+	require.True(t, mockT.skipped)
+}
+
+func TestCommon_FlakyKnown(t *testing.T) {
+	mockT := newCommonTMock()
+	comm := Common{TestingT: mockT, Provider: newProviderMockCommon("name", "fullName")}
+
+	comm.Flaky()
+	comm.Known()
+	comm.Fail()
+
+	require.True(t, comm.GetResult().StatusDetails.Flaky)
+	require.True(t, comm.GetResult().StatusDetails.Known)
+	require.False(t, comm.GetResult().StatusDetails.Muted)
+	require.True(t, mockT.failed)
+}
+
+func TestCommon_WithTimeout(t *testing.T) {
+	mockT := newCommonTMock()
+	comm := &Common{TestingT: mockT, Provider: newProviderMockCommon("name", "fullName")}
+
+	comm.WithTimeout(time.Second)
+	require.Len(t, mockT.logs, 1)
+	require.Contains(t, mockT.logs[0], "WithTimeout is ignored")
+
+	var timeout time.Duration
+	comm.SetTimeoutHandler(func(d time.Duration) { timeout = d })
+	comm.WithTimeout(time.Second)
+	require.Equal(t, time.Second, timeout)
+	require.Len(t, mockT.logs, 1)
+}
+
+func TestCommon_Context(t *testing.T) {
+	mockT := newCommonTMock()
+	comm := &Common{TestingT: mockT, Provider: newProviderMockCommon("name", "fullName")}
+
+	require.NoError(t, comm.Context().Err())
+	comm.Fail()
+	require.ErrorIs(t, comm.Context().Err(), context.Canceled)
+
+	finished := &Common{TestingT: mockT, Provider: newProviderMockCommon("name", "fullName")}
+	finished.CancelContext()
+	require.ErrorIs(t, finished.Context().Err(), context.Canceled)
+}
+
 func TestCommon_SkipOnPrint(t *testing.T) {
 	mockT := newCommonTMock()
 	comm := Common{TestingT: mockT, Provider: &providerMockCommon{testMetaMock: &testMetaMockCommon{result: &allure.Result{ToPrint: true}}}}
@@ -428,3 +515,10 @@ func TestCopyLabels(t *testing.T) {
 	require.Len(t, target.GetLabels(allure.Owner), 1)
 	require.Equal(t, owner, target.GetLabels(allure.Owner)[0])
 }
+
+func TestCommon_MaskedValues(t *testing.T) {
+	res := &allure.Result{Parameters: []*allure.Parameter{allure.NewParameter("token", "secret-token").Masked()}}
+	mockT := newCommonTMock()
+	comm := Common{TestingT: mockT, Provider: &providerMockCommon{testMetaMock: &testMetaMockCommon{result: res}}}
+	require.Equal(t, []string{"secret-token"}, comm.MaskedValues())
+}
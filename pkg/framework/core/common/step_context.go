@@ -1,9 +1,12 @@
 package common
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"runtime/debug"
 	"sync"
+	"time"
 
 	"github.com/ozontech/allure-go/pkg/allure"
 	"github.com/ozontech/allure-go/pkg/framework/asserts_wrapper/helper"
@@ -29,6 +32,7 @@ type StepT interface {
 	BrokenNow()
 	Name() string
 	GetRealT() provider.TestingT
+	Context() context.Context
 }
 
 type InternalStepCtx interface {
@@ -36,6 +40,7 @@ type InternalStepCtx interface {
 
 	ExecutionContextName() string
 	WG() *sync.WaitGroup
+	FinishContext()
 }
 
 type stepCtx struct {
@@ -49,6 +54,12 @@ type stepCtx struct {
 	require provider.Asserts
 
 	wg sync.WaitGroup
+
+	ctxMu      sync.Mutex
+	ctx        context.Context
+	cancels    []context.CancelFunc
+	asyncSteps int
+	finished   bool
 }
 
 func NewStepCtx(t StepT, p StepProvider, stepName string, params ...*allure.Parameter) InternalStepCtx {
@@ -56,6 +67,7 @@ func NewStepCtx(t StepT, p StepProvider, stepName string, params ...*allure.Para
 	newCtx := &stepCtx{t: t, p: p, currentStep: currentStep, wg: sync.WaitGroup{}}
 	newCtx.asserts = helper.NewAssertsHelper(newCtx)
 	newCtx.require = helper.NewRequireHelper(newCtx)
+	newCtx.withParentContext(t.Context())
 	return newCtx
 }
 
@@ -64,9 +76,91 @@ func (ctx *stepCtx) NewChildCtx(stepName string, params ...*allure.Parameter) In
 	newCtx := &stepCtx{t: ctx.t, p: ctx.p, currentStep: currentStep, parentStep: ctx, wg: sync.WaitGroup{}}
 	newCtx.asserts = helper.NewAssertsHelper(newCtx)
 	newCtx.require = helper.NewRequireHelper(newCtx)
+	newCtx.withParentContext(ctx.Context())
 	return newCtx
 }
 
+func (ctx *stepCtx) withParentContext(parent context.Context) {
+	stepContext, cancel := context.WithCancel(parent)
+	ctx.ctx = stepContext
+	ctx.cancels = []context.CancelFunc{cancel}
+}
+
+// initContext creates the context of the step made without NewStepCtx, ctxMu must be held
+func (ctx *stepCtx) initContext() {
+	if ctx.ctx == nil {
+		ctx.withParentContext(context.Background())
+	}
+}
+
+// Context returns the context of the step. It is cancelled when the step finishes or fails,
+// when its deadline (see WithContextTimeout) passes or when the context of the parent step or test is cancelled.
+func (ctx *stepCtx) Context() context.Context {
+	ctx.ctxMu.Lock()
+	defer ctx.ctxMu.Unlock()
+
+	ctx.initContext()
+
+	return ctx.ctx
+}
+
+// WithContextTimeout sets the deadline of the step context counted from now.
+// The deadline cancels the context only, the step is not failed by it.
+func (ctx *stepCtx) WithContextTimeout(timeout time.Duration) {
+	ctx.ctxMu.Lock()
+	defer ctx.ctxMu.Unlock()
+
+	ctx.initContext()
+
+	stepContext, cancel := context.WithTimeout(ctx.ctx, timeout)
+	ctx.ctx = stepContext
+	ctx.cancels = append(ctx.cancels, cancel)
+}
+
+// CancelContext cancels the context of the step
+func (ctx *stepCtx) CancelContext() {
+	ctx.ctxMu.Lock()
+	defer ctx.ctxMu.Unlock()
+
+	ctx.cancelContext()
+}
+
+// FinishContext cancels the context of the finished step once the async steps started by it are done
+func (ctx *stepCtx) FinishContext() {
+	ctx.ctxMu.Lock()
+	defer ctx.ctxMu.Unlock()
+
+	ctx.finished = true
+	if ctx.asyncSteps == 0 {
+		ctx.cancelContext()
+	}
+}
+
+// asyncStepStarted and asyncStepDone count the running async steps started by the step
+func (ctx *stepCtx) asyncStepStarted() {
+	ctx.ctxMu.Lock()
+	defer ctx.ctxMu.Unlock()
+
+	ctx.asyncSteps++
+}
+
+func (ctx *stepCtx) asyncStepDone() {
+	ctx.ctxMu.Lock()
+	defer ctx.ctxMu.Unlock()
+
+	ctx.asyncSteps--
+	if ctx.finished && ctx.asyncSteps == 0 {
+		ctx.cancelContext()
+	}
+}
+
+// cancelContext cancels the context of the step, ctxMu must be held
+func (ctx *stepCtx) cancelContext() {
+	for _, cancel := range ctx.cancels {
+		cancel()
+	}
+}
+
 func (ctx *stepCtx) Name() string {
 	return ctx.t.Name()
 }
@@ -88,6 +182,7 @@ func (ctx *stepCtx) ExecutionContextName() string {
 }
 
 func (ctx *stepCtx) FailNow() {
+	ctx.CancelContext()
 	ctx.currentStep.Failed()
 	if ctx.parentStep != nil {
 		ctx.parentStep.Fail()
@@ -146,6 +241,32 @@ func (ctx *stepCtx) WithNewAttachment(name string, mimeType allure.MimeType, con
 	ctx.currentStep.WithAttachments(allure.NewAttachment(name, mimeType, content))
 }
 
+// WithNewAttachmentFromReader writes the content to the sink of the execution context and adds the attachment to the step
+func (ctx *stepCtx) WithNewAttachmentFromReader(name string, mimeType allure.MimeType, content io.Reader) error {
+	attachment, err := allure.NewAttachmentFromReaderTo(ctx.p.ExecutionContext().GetSink(), name, mimeType, content)
+	if err != nil {
+		return err
+	}
+
+	ctx.currentStep.WithAttachments(attachment)
+	return nil
+}
+
+// WithNewAttachmentFromFile puts the file to the sink of the execution context and adds the attachment to the step
+func (ctx *stepCtx) WithNewAttachmentFromFile(name string, mimeType allure.MimeType, path string, mode allure.AttachMode) error {
+	attachment, err := allure.NewAttachmentFromFileTo(ctx.p.ExecutionContext().GetSink(), name, mimeType, path, mode)
+	if err != nil {
+		return err
+	}
+
+	ctx.currentStep.WithAttachments(attachment)
+	return nil
+}
+
+func (ctx *stepCtx) WithNewLazyAttachment(name string, mimeType allure.MimeType, producer func(w io.Writer) error) {
+	ctx.currentStep.WithAttachments(allure.NewLazyAttachment(name, mimeType, producer))
+}
+
 func (ctx *stepCtx) LogStep(args ...interface{}) {
 	newStep := allure.NewSimpleStep(fmt.Sprintln(args...))
 	ctx.currentStep.WithChild(newStep)
@@ -158,6 +279,23 @@ func (ctx *stepCtx) LogfStep(format string, args ...interface{}) {
 	ctx.Logf(format, args...)
 }
 
+// MaskedValues returns the values of the masked parameters of the step, of its parent steps and of the test,
+// the assertions of the step mask them
+func (ctx *stepCtx) MaskedValues() []string {
+	masked := ctx.currentStep.MaskedValues()
+
+	var parent interface{} = ctx.t
+	if ctx.parentStep != nil {
+		parent = ctx.parentStep
+	}
+
+	if maskingParent, ok := parent.(interface{ MaskedValues() []string }); ok {
+		masked = append(masked, maskingParent.MaskedValues()...)
+	}
+
+	return masked
+}
+
 func (ctx *stepCtx) Step(step *allure.Step) {
 	ctx.currentStep.WithChild(step)
 }
@@ -173,6 +311,7 @@ func (ctx *stepCtx) WithNewStep(stepName string, step func(ctx provider.StepCtx)
 	defer func() {
 		r := recover()
 		newCtx.WG().Wait()
+		newCtx.FinishContext()
 		newCtx.CurrentStep().Finish()
 		if r != nil {
 			ctxName := newCtx.ExecutionContextName()
@@ -192,14 +331,17 @@ func (ctx *stepCtx) WithNewAsyncStep(stepName string, step func(ctx provider.Ste
 		wg = ctx.parentStep.WG()
 	}
 	wg.Add(1)
+	ctx.asyncStepStarted()
 
 	go func() {
 		defer wg.Done()
+		defer ctx.asyncStepDone()
 		ctx.WithNewStep(stepName, step, params...)
 	}()
 }
 
 func (ctx *stepCtx) Fail() {
+	ctx.CancelContext()
 	ctx.currentStep.Failed()
 	if ctx.parentStep != nil {
 		ctx.parentStep.Fail()
@@ -208,6 +350,7 @@ func (ctx *stepCtx) Fail() {
 }
 
 func (ctx *stepCtx) Broken() {
+	ctx.CancelContext()
 	ctx.currentStep.Broken()
 	if ctx.parentStep != nil {
 		ctx.parentStep.Broken()
@@ -216,6 +359,7 @@ func (ctx *stepCtx) Broken() {
 }
 
 func (ctx *stepCtx) BrokenNow() {
+	ctx.CancelContext()
 	ctx.currentStep.Broken()
 	if ctx.parentStep != nil {
 		ctx.parentStep.Broken()
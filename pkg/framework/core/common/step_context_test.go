@@ -1,6 +1,9 @@
 package common
 
 import (
+	"context"
+	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -89,6 +92,10 @@ func (m *providerTMockStep) GetRealT() provider.TestingT {
 	return m.testingT
 }
 
+func (m *providerTMockStep) Context() context.Context {
+	return context.Background()
+}
+
 func (m *providerTMockStep) SetRealT(realT provider.TestingT) {
 	m.testingT = realT
 }
@@ -122,6 +129,7 @@ type executionCtxMock struct {
 
 	steps       []*allure.Step
 	attachments []*allure.Attachment
+	sink        allure.Sink
 }
 
 func newExecutionCtxMock(name string) *executionCtxMock {
@@ -144,6 +152,10 @@ func (m *executionCtxMock) GetName() string {
 	return m.name
 }
 
+func (m *executionCtxMock) GetSink() allure.Sink {
+	return m.sink
+}
+
 func TestNewStepCtx(t *testing.T) {
 	params := allure.NewParameters("p1", "v1", "p2", "v2")
 	ctx := NewStepCtx(
@@ -374,6 +386,25 @@ func TestStepCtx_WithNewAttachment(t *testing.T) {
 	require.Equal(t, []byte("attach text 1"), step.Attachments[0].GetContent())
 }
 
+func TestStepCtx_WithNewAttachmentFromReader(t *testing.T) {
+	mockT := new(providerTMockStep)
+	step := allure.NewSimpleStep("testStep")
+	execCtx := newExecutionCtxMock(constants.TestContextName)
+	execCtx.sink = allure.NewMemorySink()
+
+	ctx := stepCtx{t: mockT, p: &providerMockStep{executionContext: execCtx}, currentStep: step}
+	require.NoError(t, ctx.WithNewAttachmentFromReader("attach1", allure.Text, strings.NewReader("attach text 1")))
+	require.Len(t, step.Attachments, 1)
+	require.Equal(t, "attach1", step.Attachments[0].Name)
+
+	content, ok := execCtx.sink.(*allure.MemorySink).File(step.Attachments[0].Source)
+	require.True(t, ok)
+	require.Equal(t, "attach text 1", string(content))
+
+	require.Error(t, ctx.WithNewAttachmentFromFile("attach2", allure.Text, filepath.Join(t.TempDir(), "not_exists.txt"), allure.CopyFile))
+	require.Len(t, step.Attachments, 1)
+}
+
 func TestStepCtx_NewStep(t *testing.T) {
 	mockT := new(providerTMockStep)
 	step := allure.NewSimpleStep("testStep")
@@ -514,3 +545,53 @@ func TestStepCtx_Name(t *testing.T) {
 	ctx.WithNewStep("new step", stepF)
 	require.Equal(t, mockT.name, actualName)
 }
+
+func TestStepCtx_Context(t *testing.T) {
+	mockT := newStepProviderMock()
+	p := &providerMockStep{executionContext: newExecutionCtxMock(constants.TestContextName)}
+	ctx := NewStepCtx(mockT, p, "testStep")
+
+	var (
+		stepContext context.Context
+		deadline    time.Time
+		hasDeadline bool
+	)
+
+	ctx.WithNewStep("new step", func(sCtx provider.StepCtx) {
+		sCtx.WithContextTimeout(time.Minute)
+		stepContext = sCtx.Context()
+		deadline, hasDeadline = stepContext.Deadline()
+		require.NoError(t, stepContext.Err())
+	})
+	require.True(t, hasDeadline)
+	require.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+	require.ErrorIs(t, stepContext.Err(), context.Canceled)
+	require.NoError(t, ctx.Context().Err())
+
+	ctx.FinishContext()
+	require.ErrorIs(t, ctx.Context().Err(), context.Canceled)
+}
+
+func TestStepCtx_Context_failedSibling(t *testing.T) {
+	mockT := newStepProviderMock()
+	p := &providerMockStep{executionContext: newExecutionCtxMock(constants.TestContextName)}
+	ctx := NewStepCtx(mockT, p, "testStep")
+
+	stopped := make(chan bool, 1)
+
+	ctx.WithNewStep("parent step", func(sCtx provider.StepCtx) {
+		sCtx.WithNewAsyncStep("waiting step", func(sCtx provider.StepCtx) {
+			select {
+			case <-sCtx.Context().Done():
+				stopped <- true
+			case <-time.After(time.Minute):
+				stopped <- false
+			}
+		})
+		sCtx.WithNewAsyncStep("failed step", func(sCtx provider.StepCtx) {
+			sCtx.Fail()
+		})
+	})
+	require.True(t, <-stopped)
+	require.ErrorIs(t, ctx.Context().Err(), context.Canceled)
+}
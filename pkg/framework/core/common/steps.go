@@ -16,6 +16,7 @@ func (c *Common) WithNewStep(stepName string, step func(ctx provider.StepCtx), p
 	defer func() {
 		r := recover()
 		stCtx.WG().Wait()
+		stCtx.FinishContext()
 		stCtx.CurrentStep().Finish()
 		if r != nil {
 			ctxName := c.ExecutionContext().GetName()
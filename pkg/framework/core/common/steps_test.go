@@ -30,6 +30,10 @@ func (m *executionContextstepsCommMock) AddStep(step *allure.Step) {
 	m.steps = append(m.steps, step)
 }
 
+func (m *executionContextstepsCommMock) GetSink() allure.Sink {
+	return nil
+}
+
 func (m *executionContextstepsCommMock) AddAttachments(attachments ...*allure.Attachment) {
 	m.attachments = append(m.attachments, attachments...)
 }
@@ -9,6 +9,7 @@ require (
 	github.com/ozontech/allure-go/pkg/allure v0.7.5
 	github.com/pkg/errors v0.9.1
 	github.com/stretchr/testify v1.7.1
+	gopkg.in/yaml.v3 v3.0.0
 )
 
 require (
@@ -16,5 +17,4 @@ require (
 	github.com/google/uuid v1.3.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	google.golang.org/protobuf v1.34.2-0.20240506121844-09393c19510d // indirect
-	gopkg.in/yaml.v3 v3.0.0 // indirect
 )
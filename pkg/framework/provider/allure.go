@@ -1,6 +1,8 @@
 package provider
 
 import (
+	"io"
+
 	"github.com/ozontech/allure-go/pkg/allure"
 )
 
@@ -41,6 +43,7 @@ type Links interface {
 	Link(link *allure.Link)
 	TmsLink(tmsCase string)
 	TmsLinks(tmsCases ...string)
+	LinkTo(linkType string, values ...string)
 }
 
 type DescriptionFields interface {
@@ -60,6 +63,9 @@ type AllureSteps interface {
 type Attachments interface {
 	WithAttachments(attachment ...*allure.Attachment)
 	WithNewAttachment(name string, mimeType allure.MimeType, content []byte)
+	WithNewAttachmentFromReader(name string, mimeType allure.MimeType, content io.Reader) error
+	WithNewAttachmentFromFile(name string, mimeType allure.MimeType, path string, mode allure.AttachMode) error
+	WithNewLazyAttachment(name string, mimeType allure.MimeType, producer func(w io.Writer) error)
 }
 
 type Parameters interface {
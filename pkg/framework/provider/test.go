@@ -1,6 +1,8 @@
 package provider
 
 import (
+	"context"
+	"io"
 	"testing"
 	"time"
 
@@ -29,6 +31,11 @@ type T interface {
 	Broken()
 	BrokenNow()
 	SkipOnPrint()
+	Flaky()
+	Known()
+	Muted(reason string)
+	WithTimeout(timeout time.Duration)
+	Context() context.Context
 	Assert() Asserts
 	Require() Asserts
 	Run(testName string, testBody func(T), tags ...string) *allure.Result
@@ -52,6 +59,9 @@ type StepCtx interface {
 
 	WithAttachments(attachment ...*allure.Attachment)
 	WithNewAttachment(name string, mimeType allure.MimeType, content []byte)
+	WithNewAttachmentFromReader(name string, mimeType allure.MimeType, content io.Reader) error
+	WithNewAttachmentFromFile(name string, mimeType allure.MimeType, path string, mode allure.AttachMode) error
+	WithNewLazyAttachment(name string, mimeType allure.MimeType, producer func(w io.Writer) error)
 
 	Assert() Asserts
 	Require() Asserts
@@ -73,6 +83,9 @@ type StepCtx interface {
 	Break(args ...interface{})
 	Breakf(format string, args ...interface{})
 	Name() string
+
+	Context() context.Context
+	WithContextTimeout(timeout time.Duration)
 }
 
 // Asserts ...
@@ -0,0 +1,273 @@
+package runner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ozontech/allure-go/pkg/allure"
+)
+
+// prerequisiteLinkType is the link type of the skipped test to the result of its prerequisite. Its pattern
+// (`$ALLURE_LINK_PREREQUISITE_PATTERN` or allure.RegisterLinkType) gets the `{uuid}` and the `{testCaseId}`
+// of the prerequisite's result. Without the pattern the link refers to the result file of the prerequisite.
+const prerequisiteLinkType = "prerequisite"
+
+// DependsOn declares the prerequisites of the test: the test is started after them and skipped if any of them
+// doesn't pass. The tests are referred by their names. The prerequisites are run sequentially, their t.Parallel() is ignored.
+// The dependency cycle fails the runner at once.
+func (r *runner) DependsOn(testName string, prerequisites ...string) {
+	if r.dependencies == nil {
+		r.dependencies = make(map[string][]string)
+	}
+
+	r.dependencies[testName] = append(r.dependencies[testName], prerequisites...)
+
+	if err := checkDependencies(r.dependencies); err != nil {
+		r.realT().Fatalf("allure-go: %s", err)
+	}
+}
+
+// collectDependencies asks AllureDependencySuite for the prerequisites of the collected tests
+func collectDependencies(runner *suiteRunner, suite AllureDependencySuite) {
+	for _, test := range runner.tests {
+		testName := test.GetMeta().GetResult().Name
+		if prerequisites := suite.DependsOn(testName); len(prerequisites) > 0 {
+			runner.DependsOn(testName, prerequisites...)
+		}
+	}
+}
+
+// checkDependencies returns the error if the dependencies of the tests have a cycle
+func checkDependencies(dependencies map[string][]string) error {
+	const (
+		visiting = iota + 1
+		visited
+	)
+
+	var (
+		states = make(map[string]int, len(dependencies))
+		path   []string
+		visit  func(testName string) error
+	)
+
+	visit = func(testName string) error {
+		switch states[testName] {
+		case visiting:
+			for i, name := range path {
+				if name == testName {
+					return fmt.Errorf("dependency cycle of the tests: %s -> %s", strings.Join(path[i:], " -> "), testName)
+				}
+			}
+		case visited:
+			return nil
+		}
+
+		states[testName] = visiting
+		path = append(path, testName)
+
+		for _, prerequisite := range dependencies[testName] {
+			if err := visit(prerequisite); err != nil {
+				return err
+			}
+		}
+
+		path = path[:len(path)-1]
+		states[testName] = visited
+
+		return nil
+	}
+
+	for testName := range dependencies {
+		if err := visit(testName); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// warnUnknownPrerequisites prints the prerequisites matching no test of the suite, so a typo doesn't silently
+// turn the dependency off. It is called before the tests are filtered by the test plan and the labels.
+func (r *runner) warnUnknownPrerequisites() {
+	if len(r.dependencies) == 0 {
+		return
+	}
+
+	keyByName := r.testKeysByName()
+
+	testNames := make([]string, 0, len(r.dependencies))
+	for testName := range r.dependencies {
+		testNames = append(testNames, testName)
+	}
+	sort.Strings(testNames)
+
+	for _, testName := range testNames {
+		for _, prerequisite := range r.dependencies[testName] {
+			if _, ok := keyByName[prerequisite]; !ok {
+				fmt.Printf("Prerequisite %s of the test %s matches no test of %s, the dependency is ignored\n", prerequisite, testName, r.t().Name())
+			}
+		}
+	}
+}
+
+// orderByDependencies moves the prerequisites of the tests before them keeping the order of the other tests
+func (r *runner) orderByDependencies(keys []string) []string {
+	if len(r.dependencies) == 0 {
+		return keys
+	}
+
+	var (
+		keyByName = r.testKeysByName()
+		visited   = make(map[string]bool, len(keys))
+		ordered   = make([]string, 0, len(keys))
+		visit     func(key string)
+	)
+
+	visit = func(key string) {
+		if visited[key] {
+			return
+		}
+		visited[key] = true
+
+		for _, prerequisite := range r.dependencies[r.tests[key].GetMeta().GetResult().Name] {
+			if prerequisiteKey, ok := keyByName[prerequisite]; ok {
+				visit(prerequisiteKey)
+			}
+		}
+
+		ordered = append(ordered, key)
+	}
+
+	for _, key := range keys {
+		visit(key)
+	}
+
+	return ordered
+}
+
+// testKeysByName returns the keys of the collected tests by the test names
+func (r *runner) testKeysByName() map[string]string {
+	keys := make(map[string]string, len(r.tests))
+	for key, test := range r.tests {
+		keys[test.GetMeta().GetResult().Name] = key
+	}
+
+	return keys
+}
+
+// testState is the state of the test shared with the tests depending on it
+type testState struct {
+	name          string
+	prerequisites []*testState
+
+	parallel   sync.Once
+	done       chan struct{}
+	fullName   string
+	uuid       string
+	testCaseID string
+	status     allure.Status
+	message    string
+}
+
+// newTestStates returns the states of the collected tests if they have dependencies
+func (r *runner) newTestStates() map[string]*testState {
+	if len(r.dependencies) == 0 {
+		return nil
+	}
+
+	states := make(map[string]*testState, len(r.tests))
+	for key, test := range r.tests {
+		states[key] = &testState{name: test.GetMeta().GetResult().Name, done: make(chan struct{})}
+	}
+
+	keyByName := r.testKeysByName()
+	for _, state := range states {
+		for _, prerequisite := range r.dependencies[state.name] {
+			// the prerequisites filtered out of the run are ignored
+			if key, ok := keyByName[prerequisite]; ok {
+				state.prerequisites = append(state.prerequisites, states[key])
+				// the prerequisite is run sequentially: the test waiting for a parallel prerequisite would take
+				// one of the -test.parallel slots and could wait forever for the prerequisite to get one
+				states[key].parallel.Do(func() {})
+			}
+		}
+	}
+
+	return states
+}
+
+// parallelOnce returns sync.Once calling Parallel of the test, it is shared by the attempts of the test.
+// The Once of the prerequisite is already done, so its Parallel call is ignored.
+func (r *runner) parallelOnce(key string) *sync.Once {
+	if state, ok := r.states[key]; ok {
+		return &state.parallel
+	}
+
+	return new(sync.Once)
+}
+
+// runWithDependencies runs the test if all of its prerequisites have passed.
+// Otherwise, the test is skipped with the link to the result of the prerequisite.
+func (r *runner) runWithDependencies(t *testing.T, key string, test Test, sink allure.Sink, hooks testHooks, result SuiteResult) {
+	state, ok := r.states[key]
+	if !ok {
+		r.runWithRetries(t, key, test, sink, hooks, result)
+		return
+	}
+
+	testResult := test.GetMeta().GetResult()
+	defer func() {
+		state.fullName, state.status, state.message = testResult.FullName, testResult.Status, testResult.StatusDetails.Message
+		state.uuid, state.testCaseID = testResult.UUID.String(), testResult.TestCaseID
+		close(state.done)
+	}()
+
+	for _, prerequisite := range state.prerequisites {
+		select {
+		case <-prerequisite.done:
+		default:
+			// the prerequisites are sequential and started before the test, so the prerequisite hasn't been run
+			// at all (e.g. it is filtered out by -run) and is ignored
+			continue
+		}
+
+		if prerequisite.status != allure.Passed {
+			skipDependent(t, test, prerequisite, result)
+		}
+	}
+
+	testResult = r.runWithRetries(t, key, test, sink, hooks, result)
+}
+
+// prerequisiteLink returns the link to the result of the prerequisite
+func prerequisiteLink(prerequisite *testState) *allure.Link {
+	name := fmt.Sprintf("Prerequisite %s", prerequisite.fullName)
+
+	if _, ok := allure.GetLinkType(prerequisiteLinkType); !ok {
+		return allure.NewLink(name, allure.LINK, prerequisite.uuid+"-result.json")
+	}
+
+	link := allure.LinkTo(prerequisiteLinkType, "uuid="+prerequisite.uuid, "testCaseId="+prerequisite.testCaseID)
+	link.Name = name
+
+	return link
+}
+
+// skipDependent skips the test because its prerequisite hasn't passed
+func skipDependent(t *testing.T, test Test, prerequisite *testState, result SuiteResult) {
+	msg := fmt.Sprintf("Prerequisite %s has not passed (status: %s)", prerequisite.fullName, prerequisite.status)
+
+	testResult := test.GetMeta().GetResult()
+	testResult.Status = allure.Skipped
+	testResult.SetStatusMessage(msg)
+	if prerequisite.message != "" {
+		testResult.SetStatusTrace(fmt.Sprintf("%s: %s", prerequisite.fullName, strings.TrimSpace(prerequisite.message)))
+	}
+	testResult.Links = append(testResult.Links, prerequisiteLink(prerequisite))
+
+	result.NewResult(finishTest(t, test.GetMeta()))
+	t.Skip(msg)
+}
@@ -3,6 +3,7 @@ package runner
 import (
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/ozontech/allure-go/pkg/allure"
 	"github.com/ozontech/allure-go/pkg/framework/provider"
@@ -38,6 +39,48 @@ type AllureIDSuite interface {
 	GetAllureID(testName string) string
 }
 
+// AllureSinkSuite has a GetSink method,
+// which returns the allure.Sink all results of the suite will be written to
+type AllureSinkSuite interface {
+	GetSink() allure.Sink
+}
+
+// AllureIDStrategySuite has a GetIDStrategy method,
+// which returns the allure.IDStrategy computing the test case and history IDs of the suite's tests
+type AllureIDStrategySuite interface {
+	GetIDStrategy() allure.IDStrategy
+}
+
+// AllureRetrySuite has a RetryPolicy method,
+// which returns the number of retries of the failed test by its name
+type AllureRetrySuite interface {
+	RetryPolicy(testName string) int
+}
+
+// AllureTimeoutSuite has a TimeoutPolicy method,
+// which returns the timeout of the test by its name, zero for the default timeout of the runner
+type AllureTimeoutSuite interface {
+	TimeoutPolicy(testName string) time.Duration
+}
+
+// AllureLabelsSuite has a GetLabels method,
+// which returns the labels of the test by its name known before the test is run (see the label filter)
+type AllureLabelsSuite interface {
+	GetLabels(testName string) []*allure.Label
+}
+
+// AllurePrioritySuite has a GetPriority method,
+// which returns the priority of the test by its name: the tests with higher priority are started first
+type AllurePrioritySuite interface {
+	GetPriority(testName string) int
+}
+
+// AllureDependencySuite has a DependsOn method,
+// which returns the names of the tests the test depends on: it is started after them and skipped if any of them fails
+type AllureDependencySuite interface {
+	DependsOn(testName string) []string
+}
+
 // ParametrizedSuite suit can initialize parameters for
 // parametrized test before running hooks
 type ParametrizedSuite interface {
@@ -69,6 +112,12 @@ type TestRunner interface {
 	AfterEach(hookBody func(provider.T))
 	BeforeAll(hookBody func(provider.T))
 	AfterAll(hookBody func(provider.T))
+	SetSink(sink allure.Sink)
+	SetIDStrategy(strategy allure.IDStrategy)
+	SetRetries(retries int)
+	SetTimeout(timeout time.Duration)
+	SetOrder(order Order)
+	DependsOn(testName string, prerequisites ...string)
 	RunTests() SuiteResult
 }
 
@@ -83,6 +132,8 @@ type SuiteResult interface {
 	GetAllTestResults() []TestResult
 	GetResultByName(name string) TestResult
 	GetResultByUUID(uuid string) TestResult
+	AddError(err error)
+	GetErrors() []error
 	ToJSON() ([]byte, error)
 }
 
@@ -100,4 +151,5 @@ type internalT interface {
 	GetProvider() provider.Provider
 	WG() *sync.WaitGroup
 	GetResult() *allure.Result
+	CancelContext()
 }
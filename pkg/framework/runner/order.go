@@ -0,0 +1,115 @@
+package runner
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ozontech/allure-go/pkg/allure"
+)
+
+// Order is the order the tests of the runner are started in
+type Order string
+
+// Order constants
+const (
+	OrderAlphabetical Order = "alphabetical" // By the test name
+	OrderPriority     Order = "priority"     // By AllurePrioritySuite.GetPriority (higher first), then by the test name
+	OrderShuffle      Order = "shuffle"      // Random order, it is replayed with the same seed
+)
+
+var (
+	orderFlag = flag.String("allure-go.order", "", "order of the allure-go tests: alphabetical, priority or shuffle, $ALLURE_ORDER if not set")
+	seedFlag  = flag.Int64("allure-go.seed", 0, "seed the allure-go tests are shuffled with, $ALLURE_SEED or random if not set")
+)
+
+var (
+	seedOnce    sync.Once
+	shuffleSeed int64
+)
+
+// SetOrder sets the order the tests of the runner are started in.
+// By default, it is taken from `-allure-go.order` flag or `$ALLURE_ORDER`;
+// the tests of AllurePrioritySuite are ordered by priority, the other tests - alphabetically.
+func (r *runner) SetOrder(order Order) {
+	r.order = order
+}
+
+// orderOf returns the order of the tests of the runner
+func (r *runner) orderOf() Order {
+	switch {
+	case r.order != "":
+		return r.order
+	case *orderFlag != "":
+		return Order(*orderFlag)
+	case allure.GetConfig().Order != "":
+		return Order(allure.GetConfig().Order)
+	case r.priority != nil:
+		return OrderPriority
+	default:
+		return OrderAlphabetical
+	}
+}
+
+// getShuffleSeed returns the seed all runners of the process shuffle the tests with.
+// The seed is printed, so the order can be replayed with `-allure-go.seed` flag or `$ALLURE_SEED`.
+func getShuffleSeed() int64 {
+	seedOnce.Do(func() {
+		switch {
+		case *seedFlag != 0:
+			shuffleSeed = *seedFlag
+		case allure.GetConfig().Seed != 0:
+			shuffleSeed = allure.GetConfig().Seed
+		default:
+			shuffleSeed = time.Now().UnixNano()
+		}
+
+		fmt.Printf("allure-go: tests are shuffled with seed %d, replay the order with -allure-go.seed=%d\n", shuffleSeed, shuffleSeed)
+	})
+
+	return shuffleSeed
+}
+
+// orderedTests returns the names of the tests of the runner in the order they are started in.
+// The shuffled tests get the seed label, so the order can be replayed from the report.
+func (r *runner) orderedTests() []string {
+	names := make([]string, 0, len(r.tests))
+	for name := range r.tests {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	switch order := r.orderOf(); order {
+	case OrderAlphabetical:
+	case OrderPriority:
+		if r.priority == nil {
+			break
+		}
+
+		priorities := make(map[string]int, len(names))
+		for _, name := range names {
+			priorities[name] = r.priority(name)
+		}
+
+		sort.SliceStable(names, func(i, j int) bool {
+			return priorities[names[i]] > priorities[names[j]]
+		})
+	case OrderShuffle:
+		seed := getShuffleSeed()
+
+		rand.New(rand.NewSource(seed)).Shuffle(len(names), func(i, j int) {
+			names[i], names[j] = names[j], names[i]
+		})
+
+		for _, name := range names {
+			r.tests[name].GetMeta().GetResult().AddLabel(allure.SeedLabel(seed))
+		}
+	default:
+		fmt.Printf("allure-go: unknown order %q, tests are run in alphabetical order\n", order)
+	}
+
+	return names
+}
@@ -0,0 +1,243 @@
+package runner
+
+import (
+	"flag"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"testing"
+
+	"github.com/ozontech/allure-go/pkg/allure"
+	"github.com/ozontech/allure-go/pkg/framework/core/allure_manager/adapter"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+var (
+	retriesFlag   = flag.Int("allure-go.retries", -1, "number of retries of the failed allure-go tests, $ALLURE_RETRIES if not set")
+	markFlakyFlag = flag.Bool("allure-go.retries.flaky", false, "mark the allure-go tests passed on retry as flaky, $ALLURE_MARK_FLAKY if not set")
+)
+
+// SetRetries sets the number of retries of the failed tests of the runner.
+// By default, it is taken from `-allure-go.retries` flag or `$ALLURE_RETRIES`;
+// AllureRetrySuite overrides it for the tests of the suite.
+func (r *runner) SetRetries(retries int) {
+	r.retries = &retries
+}
+
+// retriesOf returns the number of retries of the test
+func (r *runner) retriesOf(testName string) int {
+	switch {
+	case r.retryPolicy != nil:
+		return r.retryPolicy(testName)
+	case r.retries != nil:
+		return *r.retries
+	case *retriesFlag >= 0:
+		return *retriesFlag
+	default:
+		return allure.GetConfig().Retries
+	}
+}
+
+// markFlaky returns true if the tests passed on retry must be marked flaky
+func markFlaky() bool {
+	return *markFlakyFlag || allure.GetConfig().MarkFlaky
+}
+
+// runWithRetries runs the test and retries it while it fails, at most retries times.
+// Every attempt is written as a separate result with the same HistoryID, so they are shown at the Retries tab of the report.
+// Only the last attempt is added to the SuiteResult and decides the verdict of the test, its result is returned.
+func (r *runner) runWithRetries(t *testing.T, name string, test Test, sink allure.Sink, hooks testHooks, result SuiteResult) *allure.Result {
+	retries := r.retriesOf(name)
+	if retries <= 0 {
+		return r.runTest(t, name, test, hooks, result, false)
+	}
+
+	var (
+		pristine = test.GetMeta().GetResult().Retry()
+		state    = new(retryState)
+		flaky    = markFlaky()
+	)
+
+	for attempt := 0; ; attempt++ {
+		var (
+			attemptT      = &attemptT{T: t, state: state}
+			attemptResult = NewSuiteResult(result.GetContainer())
+			attemptTest   = test
+			attemptOutput = test.GetMeta().GetResult()
+		)
+
+		attemptT.run(func() {
+			attemptOutput = r.runTest(attemptT, name, attemptTest, hooks, attemptResult, flaky && attempt > 0)
+		})
+
+		if !attemptT.Failed() || attempt == retries {
+			for _, testResult := range attemptResult.GetAllTestResults() {
+				result.NewResult(testResult)
+			}
+
+			attemptT.finish()
+
+			return attemptOutput
+		}
+
+		t.Logf("allure-go: attempt %d of %d failed, retrying", attempt+1, retries+1)
+
+		meta := adapter.NewRetryTestMeta(pristine.Retry().WithSink(sink))
+		meta.GetContainer().WithSink(sink)
+		r.addChild(result.GetContainer(), meta.GetResult())
+
+		test = &retryTest{Test: test, meta: meta}
+	}
+}
+
+// addChild adds the result to the suite container, the attempts of the parallel tests are added concurrently
+func (r *runner) addChild(container *allure.Container, result *allure.Result) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	container.AddChild(result.UUID)
+}
+
+// retryTest is the next attempt of the test with its own result and container
+type retryTest struct {
+	Test
+	meta provider.TestMeta
+}
+
+// GetMeta returns provider.TestMeta of the attempt
+func (t *retryTest) GetMeta() provider.TestMeta {
+	return t.meta
+}
+
+// retryState is shared by all attempts of the test
+type retryState struct {
+	parallel sync.Once
+}
+
+// attemptT is the TestingT of one attempt of the retried test. The attempt runs in its own goroutine,
+// its failures and skips are not passed to the real test until the attempt is known to be the last one;
+// the messages are logged to the real test as they come.
+// The failures of the subtests started by the attempt fail the real test at once.
+type attemptT struct {
+	*testing.T
+
+	state *retryState
+
+	mu      sync.Mutex
+	failed  bool
+	skipped bool
+}
+
+// run runs the attempt in the new goroutine and waits for it, so FailNow and SkipNow stop the attempt only
+func (t *attemptT) run(attempt func()) {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		defer func() {
+			if rec := recover(); rec != nil {
+				t.Errorf("attempt panicked: %v\n%s", rec, debug.Stack())
+			}
+		}()
+
+		attempt()
+	}()
+
+	<-done
+}
+
+// finish passes the verdict of the last attempt to the real test
+func (t *attemptT) finish() {
+	switch {
+	case t.Failed():
+		t.T.Fail()
+	case t.Skipped():
+		t.T.SkipNow()
+	}
+}
+
+// Fail marks the attempt as failed
+func (t *attemptT) Fail() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.failed = true
+}
+
+// Failed reports whether the attempt has failed
+func (t *attemptT) Failed() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.failed
+}
+
+// FailNow marks the attempt as failed and stops it
+func (t *attemptT) FailNow() {
+	t.Fail()
+	runtime.Goexit()
+}
+
+// Error logs the message and marks the attempt as failed
+func (t *attemptT) Error(args ...interface{}) {
+	t.Helper()
+	t.Log(args...)
+	t.Fail()
+}
+
+// Errorf logs the formatted message and marks the attempt as failed
+func (t *attemptT) Errorf(format string, args ...interface{}) {
+	t.Helper()
+	t.Logf(format, args...)
+	t.Fail()
+}
+
+// Fatal logs the message, marks the attempt as failed and stops it
+func (t *attemptT) Fatal(args ...interface{}) {
+	t.Helper()
+	t.Log(args...)
+	t.FailNow()
+}
+
+// Fatalf logs the formatted message, marks the attempt as failed and stops it
+func (t *attemptT) Fatalf(format string, args ...interface{}) {
+	t.Helper()
+	t.Logf(format, args...)
+	t.FailNow()
+}
+
+// Skip logs the message, marks the attempt as skipped and stops it
+func (t *attemptT) Skip(args ...interface{}) {
+	t.Helper()
+	t.Log(args...)
+	t.SkipNow()
+}
+
+// Skipf logs the formatted message, marks the attempt as skipped and stops it
+func (t *attemptT) Skipf(format string, args ...interface{}) {
+	t.Helper()
+	t.Logf(format, args...)
+	t.SkipNow()
+}
+
+// SkipNow marks the attempt as skipped and stops it
+func (t *attemptT) SkipNow() {
+	t.mu.Lock()
+	t.skipped = true
+	t.mu.Unlock()
+
+	runtime.Goexit()
+}
+
+// Skipped reports whether the attempt was skipped
+func (t *attemptT) Skipped() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.skipped
+}
+
+// Parallel signals that the real test is to be run in parallel, only the first attempt does it
+func (t *attemptT) Parallel() {
+	t.state.parallel.Do(t.T.Parallel)
+}
@@ -8,10 +8,13 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/ozontech/allure-go/pkg/allure"
 	"github.com/ozontech/allure-go/pkg/framework/core/allure_manager/adapter"
+	"github.com/ozontech/allure-go/pkg/framework/core/allure_manager/labelfilter"
 	"github.com/ozontech/allure-go/pkg/framework/core/allure_manager/manager"
+	"github.com/ozontech/allure-go/pkg/framework/core/allure_manager/mutelist"
 	"github.com/ozontech/allure-go/pkg/framework/core/allure_manager/testplan"
 	"github.com/ozontech/allure-go/pkg/framework/core/common"
 	"github.com/ozontech/allure-go/pkg/framework/provider"
@@ -20,8 +23,29 @@ import (
 type runner struct {
 	internalT        internalT
 	testPlan         *testplan.TestPlan
+	labelFilter      *labelfilter.Filter
+	muteList         *mutelist.MuteList
 	tests            map[string]Test
 	adjustTableTests func()
+	sink             allure.Sink
+	idStrategy       allure.IDStrategy
+	retries          *int
+	retryPolicy      func(testName string) int
+	timeout          *time.Duration
+	timeoutPolicy    func(testName string) time.Duration
+	order            Order
+	priority         func(testName string) int
+	labels           func(testName string) []*allure.Label
+	dependencies     map[string][]string
+	states           map[string]*testState
+
+	mu sync.Mutex
+}
+
+// testHooks are the hooks run around each test of the runner
+type testHooks struct {
+	beforeEach common.HookFunc
+	afterEach  common.HookFunc
 }
 
 func NewRunner(realT TestingT, suiteName string) TestRunner {
@@ -36,9 +60,11 @@ func NewRunner(realT TestingT, suiteName string) TestRunner {
 	newT.SetProvider(manager.NewProvider(providerCfg))
 
 	return &runner{
-		internalT: newT,
-		tests:     make(map[string]Test),
-		testPlan:  testplan.GetTestPlan(),
+		internalT:   newT,
+		tests:       make(map[string]Test),
+		testPlan:    testplan.GetTestPlan(),
+		labelFilter: labelfilter.GetLabelFilter(),
+		muteList:    mutelist.GetMuteList(),
 	}
 }
 
@@ -52,7 +78,7 @@ func (r *runner) realT() TestingT {
 
 func (r *runner) toRun(result *allure.Result) bool {
 	if r.testPlan != nil {
-		return r.testPlan.IsSelected(result.TestCaseID, result.FullName)
+		return r.testPlan.IsResultSelected(result)
 	}
 
 	return true
@@ -63,7 +89,7 @@ func (r *runner) filterByTestPlan() map[string]Test {
 		tests := make(map[string]Test, len(r.tests))
 
 		for fullName, testData := range r.tests {
-			if r.testPlan.IsSelected(testData.GetMeta().GetResult().TestCaseID, testData.GetMeta().GetResult().FullName) {
+			if plan.IsResultSelected(testData.GetMeta().GetResult()) {
 				tests[fullName] = testData
 			}
 		}
@@ -74,6 +100,48 @@ func (r *runner) filterByTestPlan() map[string]Test {
 	return r.tests
 }
 
+// reportUnmatchedTestPlan prints the entries of the test plan matched no test of the top-level test when it is finished
+func (r *runner) reportUnmatchedTestPlan() {
+	plan, topLevelName := r.testPlan, r.realT().Name()
+	if plan == nil || strings.Contains(topLevelName, "/") {
+		return
+	}
+
+	r.realT().Cleanup(func() { plan.PrintUnmatchedOf(topLevelName) })
+}
+
+// filterByLabels returns the tests selected by the label filter.
+// The expression referring to the labels set by the test body fails the runner unless the suite declares the labels.
+func (r *runner) filterByLabels() map[string]Test {
+	if r.labelFilter == nil {
+		return r.tests
+	}
+
+	if err := r.labelFilter.CheckLabels(r.labels != nil); err != nil {
+		r.realT().Fatalf("allure-go: %s", err)
+	}
+
+	tests := make(map[string]Test, len(r.tests))
+	for key, test := range r.tests {
+		if r.labelFilter.Match(test.GetMeta().GetResult()) {
+			tests[key] = test
+		}
+	}
+
+	return tests
+}
+
+// applyLabels adds the labels declared by AllureLabelsSuite to the collected tests
+func (r *runner) applyLabels() {
+	if r.labels == nil {
+		return
+	}
+
+	for _, test := range r.tests {
+		test.GetMeta().GetResult().AddLabel(r.labels(test.GetMeta().GetResult().Name)...)
+	}
+}
+
 func (r *runner) NewTest(testName string, testBody func(provider.T), tags ...string) {
 	fullName := fmt.Sprintf("%s/%s", r.t().Name(), testName)
 
@@ -108,6 +176,44 @@ func (r *runner) AfterAll(hookBody func(provider.T)) {
 	r.internalT.GetProvider().GetSuiteMeta().SetAfterAll(hookBody)
 }
 
+// SetSink sets the allure.Sink all results of the runner will be written to.
+// If sink is nil, the process-wide allure.Sink is used.
+func (r *runner) SetSink(sink allure.Sink) {
+	r.sink = sink
+}
+
+// SetIDStrategy sets the allure.IDStrategy computing the test case and history IDs of all tests of the runner.
+// If strategy is nil, the process-wide allure.IDStrategy is used.
+func (r *runner) SetIDStrategy(strategy allure.IDStrategy) {
+	r.idStrategy = strategy
+}
+
+// applyIDStrategy sets the IDStrategy to all collected tests and recomputes their IDs
+func (r *runner) applyIDStrategy() {
+	for _, test := range r.tests {
+		test.GetMeta().GetResult().WithIDStrategy(r.idStrategy)
+	}
+}
+
+// applySink sets the sink to the suite container and to all collected tests
+func (r *runner) applySink(sink allure.Sink) {
+	r.t().GetProvider().GetSuiteMeta().GetContainer().WithSink(sink)
+
+	for _, test := range r.tests {
+		test.GetMeta().GetResult().WithSink(sink)
+		test.GetMeta().GetContainer().WithSink(sink)
+	}
+}
+
+// flushResults waits until all results of the batch are written.
+// Writing errors are added to the suite result and fail the suite.
+func (r *runner) flushResults(batch *allure.Batch, result SuiteResult) {
+	if err := batch.Flush(); err != nil {
+		result.AddError(err)
+		r.t().Errorf("Failed to write allure results: %s", err)
+	}
+}
+
 func (r *runner) RunTests() SuiteResult {
 	var (
 		wg = &sync.WaitGroup{}
@@ -116,25 +222,30 @@ func (r *runner) RunTests() SuiteResult {
 		parentTestMeta  = r.t().GetProvider().GetTestMeta()
 
 		result         = NewSuiteResult(parentSuiteMeta.GetContainer())
+		batch          = allure.DefaultWriter().NewBatch(r.sink)
 		beforeAllHook  = common.CarriedHook(common.BeforeAll, parentSuiteMeta.GetBeforeAll)
 		afterAllHook   = common.CarriedHook(common.AfterAll, parentSuiteMeta.GetAfterAll)
 		beforeEachHook = common.CarriedHook(common.BeforeEach, parentTestMeta.GetBeforeEach)
 		afterEachHook  = common.CarriedHook(common.AfterEach, parentTestMeta.GetAfterEach)
 	)
 
+	r.reportUnmatchedTestPlan()
 	r.realT().Run(parentSuiteMeta.GetSuiteName(), func(t *testing.T) {
 		oldParentT := r.realT()
 		r.t().SetRealT(t)
 
 		defer r.t().SetRealT(oldParentT)
 
+		defer r.flushResults(batch, result)
 		defer wg.Wait()
 		defer finishSuite(r.internalT.GetProvider())
+		defer r.t().CancelContext()
 		defer func() { _, _ = runHook(r.t(), afterAllHook) }()
 
 		for _, test := range r.tests {
 			result.GetContainer().AddChild(test.GetMeta().GetResult().UUID)
 		}
+		r.applySink(batch)
 
 		// before all hook
 		ok, err := runHook(r.t(), beforeAllHook)
@@ -167,7 +278,12 @@ func (r *runner) RunTests() SuiteResult {
 			r.adjustTableTests()
 		}
 
+		r.applyLabels()
+		r.applyIDStrategy()
+		r.warnUnknownPrerequisites()
 		r.tests = r.filterByTestPlan()
+		r.tests = r.filterByLabels()
+		r.applySink(batch)
 
 		if len(r.tests) == 0 {
 			r.t().Skipf("No tests to run for suite %s", r.t().Name())
@@ -183,45 +299,17 @@ func (r *runner) RunTests() SuiteResult {
 			r.t().SetRealT(t)
 			defer r.t().SetRealT(oldTestT)
 
-			for _, testData := range r.tests {
-				test := testData
+			hooks := testHooks{beforeEach: beforeEachHook, afterEach: afterEachHook}
+
+			r.states = r.newTestStates()
+
+			for _, name := range r.orderByDependencies(r.orderedTests()) {
+				name, test := name, r.tests[name]
 				wg.Add(1)
 				r.realT().Run(test.GetMeta().GetResult().Begin().Name, func(t *testing.T) {
 					defer wg.Done()
-					defer func() {
-						result.NewResult(finishTest(t, test.GetMeta()))
-					}()
-					testT := setupTest(t, r.t().GetProvider(), test.GetMeta())
-
-					// after each hook
-					defer func() {
-						_, _ = runHook(testT, afterEachHook)
-					}()
-
-					// catch panic in test body context
-					defer func() {
-						rec := recover()
-						if rec != nil {
-							ctxName := testT.GetProvider().ExecutionContext().GetName()
-							errMsg := fmt.Sprintf("%s panicked: %v\n%s", ctxName, rec, debug.Stack())
-							common.TestError(testT, testT.GetProvider(), testT.GetProvider().ExecutionContext().GetName(), errMsg)
-						}
-					}()
-
-					// before each hook
-					ok, err = runHook(testT, beforeEachHook)
-					if err != nil {
-						result = setupErrorHandler("Test Setup failed", err, test.GetMeta(), result)
-						return
-					}
-					if !ok {
-						result = setupErrorHandler("Test Setup failed", fmt.Errorf("assertion error due test setup"), test.GetMeta(), result)
-						return
-					}
-
-					testT.GetProvider().TestContext()
-					defer testT.WG().Wait()
-					test.GetBody()(testT)
+
+					r.runWithDependencies(t, name, test, batch, hooks, result)
 				})
 			}
 		})
@@ -229,6 +317,79 @@ func (r *runner) RunTests() SuiteResult {
 	return result
 }
 
+// runTest runs the hooks and the body of the test, prints its result and returns it.
+// If flaky is true, the passed test is marked flaky (see allure.StatusDetail).
+// The test exceeding its timeout is finalized without waiting for it (see timeoutTest).
+func (r *runner) runTest(t TestingT, name string, test Test, hooks testHooks, result SuiteResult, flaky bool) *allure.Result {
+	testDeadline := newDeadline(r.timeoutOf(name))
+	defer testDeadline.stop()
+
+	// the meta the result is printed from, it is detached from the test body if the test times out
+	meta := test.GetMeta()
+	defer func() {
+		if flaky && !t.Failed() && !t.Skipped() {
+			meta.GetResult().StatusDetails.Flaky = true
+		}
+
+		result.NewResult(finishTest(t, meta))
+	}()
+	deadlineT := &timeoutT{TestingT: t, deadline: testDeadline, parallel: r.parallelOnce(name)}
+	testT := setupTest(deadlineT, r.t().GetProvider(), test.GetMeta())
+	testT.SetTimeoutHandler(testDeadline.set)
+	testDeadline.onOverdue(testT.CancelContext)
+	defer testT.CancelContext()
+	r.muteList.Apply(testT.GetResult(), testT.Muted)
+
+	finished := testDeadline.run(func() {
+		defer deadlineT.runLateCleanups()
+
+		runTestBody(testT, test, hooks, result, testDeadline)
+	})
+
+	if !finished {
+		meta = timeoutTest(t, r.t().GetProvider(), testT, testDeadline.get(), hooks)
+	} else if dump, ok := testDeadline.overdue(); ok {
+		overdueTest(t, testT, testDeadline.get(), dump)
+	}
+
+	return meta.GetResult()
+}
+
+// runTestBody runs the before each hook, the body and the after each hook of the test
+func runTestBody(testT *common.Common, test Test, hooks testHooks, result SuiteResult, testDeadline *deadline) {
+	// after each hook, it is run by timeoutTest if the test has timed out
+	defer func() {
+		if !testDeadline.isExpired() {
+			_, _ = runHook(testT, hooks.afterEach)
+		}
+	}()
+
+	// catch panic in test body context
+	defer func() {
+		rec := recover()
+		if rec != nil {
+			ctxName := testT.GetProvider().ExecutionContext().GetName()
+			errMsg := fmt.Sprintf("%s panicked: %v\n%s", ctxName, rec, debug.Stack())
+			common.TestError(testT, testT.GetProvider(), testT.GetProvider().ExecutionContext().GetName(), errMsg)
+		}
+	}()
+
+	// before each hook
+	ok, err := runHook(testT, hooks.beforeEach)
+	if err != nil {
+		setupErrorHandler("Test Setup failed", err, test.GetMeta(), result)
+		return
+	}
+	if !ok {
+		setupErrorHandler("Test Setup failed", fmt.Errorf("assertion error due test setup"), test.GetMeta(), result)
+		return
+	}
+
+	testT.GetProvider().TestContext()
+	defer testT.WG().Wait()
+	test.GetBody()(testT)
+}
+
 func Run(t *testing.T, testName string, testBody func(provider.T), tags ...string) *allure.Result {
 	var (
 		newT        = common.NewT(t)
@@ -247,11 +408,29 @@ func Run(t *testing.T, testName string, testBody func(provider.T), tags ...strin
 }
 
 func setupTest(t TestingT, parentProvider provider.Provider, meta provider.TestMeta) *common.Common {
+	var (
+		testT          = newTestT(t, parentProvider)
+		parentTestMeta = parentProvider.GetTestMeta()
+	)
+
+	testT.TestContext()
+	meta.SetBeforeEach(parentTestMeta.GetBeforeEach())
+	meta.SetAfterEach(parentTestMeta.GetAfterEach())
+	if parentSuite := testT.Provider.GetSuiteMeta().GetParentSuite(); parentSuite != "" {
+		meta.GetResult().WithParentSuite(parentSuite)
+	}
+	meta.SetResult(copyLabels(parentProvider.GetResult(), meta.GetResult()))
+	testT.SetTestMeta(meta)
+
+	return testT
+}
+
+// newTestT returns the T of the test with the provider of the suite of the parent provider
+func newTestT(t TestingT, parentProvider provider.Provider) *common.Common {
 	var (
 		testT = common.NewT(t)
 
 		parentSuiteMeta = parentProvider.GetSuiteMeta()
-		parentTestMeta  = parentProvider.GetTestMeta()
 
 		packageName     = parentSuiteMeta.GetPackageName()
 		suiteName       = parentSuiteMeta.GetSuiteName()
@@ -267,15 +446,6 @@ func setupTest(t TestingT, parentProvider provider.Provider, meta provider.TestM
 	)
 	testT.SetProvider(manager.NewProvider(cfg))
 
-	testT.TestContext()
-	meta.SetBeforeEach(parentTestMeta.GetBeforeEach())
-	meta.SetAfterEach(parentTestMeta.GetAfterEach())
-	if parentSuite := testT.Provider.GetSuiteMeta().GetParentSuite(); parentSuite != "" {
-		meta.GetResult().WithParentSuite(parentSuite)
-	}
-	meta.SetResult(copyLabels(parentProvider.GetResult(), meta.GetResult()))
-	testT.SetTestMeta(meta)
-
 	return testT
 }
 
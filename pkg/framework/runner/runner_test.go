@@ -3,10 +3,18 @@ package runner
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/ozontech/allure-go/pkg/allure"
+	"github.com/ozontech/allure-go/pkg/framework/core/allure_manager/labelfilter"
+	"github.com/ozontech/allure-go/pkg/framework/core/allure_manager/mutelist"
+	"github.com/ozontech/allure-go/pkg/framework/core/allure_manager/testplan"
 	"github.com/ozontech/allure-go/pkg/framework/core/common"
 	"github.com/ozontech/allure-go/pkg/framework/core/constants"
 	"github.com/ozontech/allure-go/pkg/framework/provider"
@@ -31,6 +39,10 @@ func (m *executionContextRunnerMock) AddStep(step *allure.Step) {
 	m.steps = append(m.steps, step)
 }
 
+func (m *executionContextRunnerMock) GetSink() allure.Sink {
+	return nil
+}
+
 func (m *executionContextRunnerMock) AddAttachments(attachments ...*allure.Attachment) {
 	m.attachments = append(m.attachments, attachments...)
 }
@@ -504,3 +516,607 @@ func TestRunner_NewTest(t *testing.T) {
 	r.tests[testKey].GetBody()(r.t())
 	require.True(t, flag)
 }
+
+func TestRunner_SetSink(t *testing.T) {
+	sink := allure.NewMemorySink()
+
+	r := NewRunner(t, "suiteTest")
+	r.SetSink(sink)
+	r.NewTest("sinkTest", func(t provider.T) {
+		t.WithNewAttachment("attach", allure.Text, []byte("text"))
+		t.Require().NoError(t.WithNewAttachmentFromReader("reader", allure.Text, strings.NewReader("reader text")))
+	})
+	r.RunTests()
+
+	results, err := sink.Results()
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "sinkTest", results[0].Name)
+	require.Len(t, results[0].Attachments, 2)
+
+	content, ok := sink.File(results[0].Attachments[0].Source)
+	require.True(t, ok)
+	require.Equal(t, "text", string(content))
+
+	content, ok = sink.File(results[0].Attachments[1].Source)
+	require.True(t, ok)
+	require.Equal(t, "reader text", string(content))
+}
+
+func TestRunner_SetSink_linkFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "some.log")
+	require.NoError(t, os.WriteFile(path, []byte("some content"), 0o644))
+
+	r := NewRunner(t, "suiteTest")
+	r.SetSink(allure.NewFileSink(filepath.Join(dir, "allure-results")))
+	r.NewTest("linkTest", func(t provider.T) {
+		t.Require().NoError(t.WithNewAttachmentFromFile("log", "", path, allure.LinkFile))
+	})
+	r.RunTests()
+
+	attachments, err := filepath.Glob(filepath.Join(dir, "allure-results", "*-attachment.log"))
+	require.NoError(t, err)
+	require.Len(t, attachments, 1)
+
+	source, err := os.Stat(path)
+	require.NoError(t, err)
+	linked, err := os.Stat(attachments[0])
+	require.NoError(t, err)
+	require.True(t, os.SameFile(source, linked), "the attachment must be the hardlink of the file")
+}
+
+func TestRunner_SetIDStrategy(t *testing.T) {
+	sink := allure.NewMemorySink()
+
+	r := NewRunner(t, "suiteTest")
+	r.SetSink(sink)
+	r.SetIDStrategy(allure.AllureIDStrategy{})
+	r.NewTest("idTest", func(t provider.T) {
+		t.AllureID("1234")
+	})
+	r.RunTests()
+
+	results, err := sink.Results()
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "1234", results[0].TestCaseID)
+}
+
+func TestSuiteResult_Errors(t *testing.T) {
+	result := NewSuiteResult(allure.NewContainer())
+	require.Empty(t, result.GetErrors())
+
+	err := fmt.Errorf("whoops")
+	result.AddError(err)
+	require.Equal(t, []error{err}, result.GetErrors())
+}
+
+func TestRunner_SetRetries(t *testing.T) {
+	t.Setenv("ALLURE_MARK_FLAKY", "true")
+	sink := allure.NewMemorySink()
+
+	var attempts int
+
+	r := NewRunner(t, "suiteTest")
+	r.SetSink(sink)
+	r.SetRetries(2)
+	r.NewTest("flakyTest", func(t provider.T) {
+		t.Epic("retries")
+		attempts++
+		if attempts == 1 {
+			t.Fatalf("attempt %d failed", attempts)
+		}
+	})
+	suiteResult := r.RunTests()
+
+	require.Equal(t, 2, attempts)
+	require.Len(t, suiteResult.GetAllTestResults(), 1)
+
+	results, err := sink.Results()
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	statuses := map[allure.Status]*allure.Result{}
+	for _, result := range results {
+		statuses[result.Status] = result
+		require.Len(t, result.GetLabels(allure.Epic), 1)
+	}
+
+	require.Contains(t, statuses, allure.Failed)
+	require.Contains(t, statuses, allure.Passed)
+	require.Equal(t, statuses[allure.Failed].HistoryID, statuses[allure.Passed].HistoryID)
+	require.NotEqual(t, statuses[allure.Failed].UUID, statuses[allure.Passed].UUID)
+	require.False(t, statuses[allure.Failed].StatusDetails.Flaky)
+	require.True(t, statuses[allure.Passed].StatusDetails.Flaky)
+	require.Equal(t, statuses[allure.Passed].UUID, suiteResult.GetAllTestResults()[0].GetResult().UUID)
+}
+
+func TestAttemptT(t *testing.T) {
+	failed := &attemptT{T: t, state: new(retryState)}
+	failed.run(func() {
+		failed.Errorf("first error")
+		failed.FailNow()
+		t.Error("attempt is not stopped")
+	})
+	require.True(t, failed.Failed())
+	require.False(t, t.Failed())
+
+	panicked := &attemptT{T: t, state: new(retryState)}
+	panicked.run(func() {
+		panic("whoops")
+	})
+	require.True(t, panicked.Failed())
+
+	skipped := &attemptT{T: t, state: new(retryState)}
+	skipped.run(func() {
+		skipped.Skip("not now")
+	})
+	require.True(t, skipped.Skipped())
+	require.False(t, skipped.Failed())
+	require.False(t, t.Skipped())
+}
+
+func TestRunner_mutedTest(t *testing.T) {
+	sink := allure.NewMemorySink()
+
+	r := NewRunner(t, "suiteTest")
+	r.SetSink(sink)
+	r.NewTest("mutedTest", func(t provider.T) {
+		t.Muted("JIRA-1")
+		t.Require().Equal(1, 2)
+	})
+	r.RunTests()
+
+	results, err := sink.Results()
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, allure.Failed, results[0].Status)
+	require.True(t, results[0].StatusDetails.Muted)
+	require.Contains(t, results[0].StatusDetails.Message, "[Muted: JIRA-1]")
+}
+
+func TestRunner_muteList(t *testing.T) {
+	sink := allure.NewMemorySink()
+
+	r := NewRunner(t, "suiteTest").(*runner)
+	r.SetSink(sink)
+	r.muteList = &mutelist.MuteList{Tests: []*mutelist.Entry{
+		{Selector: t.Name() + "/muted*", Reason: "flaky on CI", Issue: "https://tracker/JIRA-1"},
+	}}
+	r.NewTest("mutedTest", func(t provider.T) {
+		t.Require().Equal(1, 2)
+	})
+	r.RunTests()
+
+	results, err := sink.Results()
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, allure.Failed, results[0].Status)
+	require.True(t, results[0].StatusDetails.Muted)
+	require.Contains(t, results[0].StatusDetails.Message, "[Muted: flaky on CI (https://tracker/JIRA-1)]")
+	require.Len(t, results[0].Links, 1)
+	require.Equal(t, "https://tracker/JIRA-1", results[0].Links[0].URL)
+}
+
+func TestRunner_SetTimeout(t *testing.T) {
+	sink := allure.NewMemorySink()
+
+	var (
+		attempts  int32
+		afterEach int
+	)
+
+	r := NewRunner(t, "suiteTest")
+	r.SetSink(sink)
+	r.SetRetries(1)
+	r.SetTimeout(time.Minute)
+	r.AfterEach(func(t provider.T) {
+		afterEach++
+	})
+	r.NewTest("hungTest", func(t provider.T) {
+		t.WithTimeout(50 * time.Millisecond)
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			// the context of the test is cancelled when it times out
+			<-t.Context().Done()
+		}
+	})
+	r.RunTests()
+
+	require.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+	require.Equal(t, 2, afterEach)
+
+	results, err := sink.Results()
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	statuses := map[allure.Status]*allure.Result{}
+	for _, result := range results {
+		statuses[result.Status] = result
+	}
+
+	require.Contains(t, statuses, allure.Passed)
+	require.Contains(t, statuses, allure.Broken)
+	require.Equal(t, "Test timed out after 50ms", statuses[allure.Broken].StatusDetails.Message)
+	require.Len(t, statuses[allure.Broken].Attachments, 1)
+	require.Equal(t, "Goroutine dump", statuses[allure.Broken].Attachments[0].Name)
+	dump, ok := sink.File(statuses[allure.Broken].Attachments[0].Source)
+	require.True(t, ok)
+	require.Contains(t, string(dump), "goroutine")
+}
+
+func TestRunner_SetTimeout_parallel(t *testing.T) {
+	sink := allure.NewMemorySink()
+
+	r := NewRunner(t, "suiteTest")
+	r.SetSink(sink)
+	r.SetTimeout(200 * time.Millisecond)
+	r.NewTest("a_parallel", func(t provider.T) {
+		// the test is paused while its sequential sibling runs, the pause doesn't count toward the timeout
+		t.Parallel()
+		time.Sleep(50 * time.Millisecond)
+	})
+	r.NewTest("b_slow", func(t provider.T) {
+		t.WithTimeout(time.Second)
+		time.Sleep(300 * time.Millisecond)
+	})
+	r.RunTests()
+
+	results, err := sink.Results()
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	for _, result := range results {
+		require.Equal(t, allure.Passed, result.Status, result.Name)
+	}
+}
+
+func TestRunner_SetTimeout_detachedBody(t *testing.T) {
+	sink := allure.NewMemorySink()
+
+	var (
+		attempts int32
+		finished = make(chan struct{})
+		cleaned  = make(chan struct{})
+	)
+
+	r := NewRunner(t, "suiteTest")
+	r.SetSink(sink)
+	r.SetRetries(1)
+	r.SetTimeout(50 * time.Millisecond)
+	r.NewTest("hungTest", func(t provider.T) {
+		if atomic.AddInt32(&attempts, 1) > 1 {
+			return
+		}
+		defer close(finished)
+
+		<-t.Context().Done()
+		// the timed out body keeps changing its result while the runner prints the snapshot of it
+		for i := 0; i < 20; i++ {
+			t.WithNewStep(fmt.Sprintf("late step %d", i), func(sCtx provider.StepCtx) {})
+		}
+		// the test is finished, the late cleanup is called when the body returns
+		t.Cleanup(func() { close(cleaned) })
+	})
+	r.RunTests()
+	<-finished
+
+	select {
+	case <-cleaned:
+	case <-time.After(time.Second):
+		require.Fail(t, "the cleanup registered after the timeout is not called")
+	}
+
+	results, err := sink.Results()
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	for _, result := range results {
+		if result.Status == allure.Broken {
+			require.Empty(t, result.Steps)
+		}
+	}
+}
+
+func TestRunner_WithTimeout_inline(t *testing.T) {
+	sink := allure.NewMemorySink()
+
+	var attempts int32
+
+	r := NewRunner(t, "suiteTest")
+	r.SetSink(sink)
+	r.SetRetries(1)
+	r.NewTest("slowTest", func(t provider.T) {
+		t.WithTimeout(20 * time.Millisecond)
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			// the inline test is not detached, its context is cancelled and it is finalized when it returns
+			<-t.Context().Done()
+		}
+	})
+	r.RunTests()
+
+	results, err := sink.Results()
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	statuses := map[allure.Status]*allure.Result{}
+	for _, result := range results {
+		statuses[result.Status] = result
+	}
+
+	require.Contains(t, statuses, allure.Passed)
+	require.Contains(t, statuses, allure.Broken)
+	require.Equal(t, "Test timed out after 20ms", statuses[allure.Broken].StatusDetails.Message)
+	require.Len(t, statuses[allure.Broken].Attachments, 1)
+	require.Equal(t, "Goroutine dump", statuses[allure.Broken].Attachments[0].Name)
+}
+
+func TestDeadline(t *testing.T) {
+	noTimeout := newDeadline(0)
+	require.True(t, noTimeout.run(func() { time.Sleep(10 * time.Millisecond) }))
+	require.False(t, noTimeout.isExpired())
+
+	// the body of the test without the timeout runs in the goroutine of the test
+	exited := make(chan bool, 2)
+	go func() {
+		defer func() { exited <- true }()
+
+		newDeadline(0).run(runtime.Goexit)
+		exited <- false
+	}()
+	require.True(t, <-exited)
+
+	extended := newDeadline(10 * time.Millisecond)
+	extended.set(time.Minute)
+	require.True(t, extended.run(func() { time.Sleep(30 * time.Millisecond) }))
+	require.Equal(t, time.Minute, extended.get())
+	extended.stop()
+
+	passed := newDeadline(time.Minute)
+	time.Sleep(10 * time.Millisecond)
+	passed.set(time.Millisecond)
+	require.False(t, passed.run(func() { time.Sleep(time.Minute) }))
+	require.True(t, passed.isExpired())
+}
+
+func TestRunner_SetOrder(t *testing.T) {
+	runOrder := func(order Order) ([]string, []*allure.Result) {
+		var started []string
+
+		sink := allure.NewMemorySink()
+		r := NewRunner(t, "suiteTest")
+		r.SetSink(sink)
+		r.SetOrder(order)
+		for _, name := range []string{"testC", "testA", "testD", "testB"} {
+			name := name
+			r.NewTest(name, func(t provider.T) {
+				started = append(started, name)
+			})
+		}
+		r.RunTests()
+
+		results, err := sink.Results()
+		require.NoError(t, err)
+
+		return started, results
+	}
+
+	started, results := runOrder(OrderAlphabetical)
+	require.Equal(t, []string{"testA", "testB", "testC", "testD"}, started)
+	for _, result := range results {
+		require.Empty(t, result.GetLabels(allure.Seed))
+	}
+
+	shuffled, results := runOrder(OrderShuffle)
+	require.ElementsMatch(t, started, shuffled)
+	for _, result := range results {
+		seed, ok := result.GetFirstLabel(allure.Seed)
+		require.True(t, ok)
+		require.Equal(t, allure.SeedLabel(getShuffleSeed()).GetValue(), seed.GetValue())
+	}
+
+	replayed, _ := runOrder(OrderShuffle)
+	require.Equal(t, shuffled, replayed)
+}
+
+func TestRunner_labelFilter(t *testing.T) {
+	sink := allure.NewMemorySink()
+
+	filter, err := labelfilter.Parse("tag=smoke && !tag=slow")
+	require.NoError(t, err)
+
+	var started []string
+
+	r := NewRunner(t, "suiteTest")
+	r.SetSink(sink)
+	r.(*runner).labelFilter = filter
+	for name, tags := range map[string][]string{
+		"smoke": {"smoke"},
+		"slow":  {"smoke", "slow"},
+		"other": nil,
+	} {
+		name := name
+		r.NewTest(name, func(t provider.T) {
+			started = append(started, name)
+		}, tags...)
+	}
+	r.RunTests()
+	require.Equal(t, []string{"smoke"}, started)
+
+	results, err := sink.Results()
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "smoke", results[0].Name)
+}
+
+func TestRunner_labelFilter_declaredLabels(t *testing.T) {
+	filter, err := labelfilter.Parse("severity in (critical, blocker)")
+	require.NoError(t, err)
+
+	var started []string
+
+	r := NewRunner(t, "suiteTest")
+	r.SetSink(allure.NewMemorySink())
+	r.(*runner).labelFilter = filter
+	r.(*runner).labels = func(testName string) []*allure.Label {
+		if testName == "critical" {
+			return []*allure.Label{allure.SeverityLabel(allure.CRITICAL)}
+		}
+
+		return nil
+	}
+	for _, name := range []string{"critical", "other"} {
+		name := name
+		r.NewTest(name, func(t provider.T) {
+			started = append(started, name)
+		})
+	}
+	r.RunTests()
+	require.Equal(t, []string{"critical"}, started)
+}
+
+func TestRunner_testPlan(t *testing.T) {
+	plan, err := testplan.Parse("tests: [{selector: 'regexp:.*/selected[0-9]'}, {id: 1042}]")
+	require.NoError(t, err)
+
+	var started []string
+
+	r := NewRunner(t, "suiteTest")
+	r.SetSink(allure.NewMemorySink())
+	r.(*runner).testPlan = plan
+	for _, name := range []string{"selected1", "selected2", "other"} {
+		name := name
+		r.NewTest(name, func(t provider.T) {
+			started = append(started, name)
+		})
+	}
+	r.RunTests()
+
+	require.Equal(t, []string{"selected1", "selected2"}, started)
+	require.Len(t, plan.Unmatched(), 1)
+}
+
+func TestRunner_DependsOn(t *testing.T) {
+	sink := allure.NewMemorySink()
+
+	var started []string
+
+	r := NewRunner(t, "suiteTest")
+	r.SetSink(sink)
+	r.DependsOn("update", "create")
+	r.DependsOn("delete", "update", "unknown")
+	r.NewTest("delete", func(t provider.T) {
+		started = append(started, "delete")
+	})
+	r.NewTest("update", func(t provider.T) {
+		started = append(started, "update")
+		t.Skip("update is not supported")
+	})
+	r.NewTest("create", func(t provider.T) {
+		started = append(started, "create")
+	})
+	r.RunTests()
+
+	require.Equal(t, []string{"create", "update"}, started)
+
+	results, err := sink.Results()
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	byName := make(map[string]*allure.Result, len(results))
+	for _, result := range results {
+		byName[result.Name] = result
+	}
+
+	require.Equal(t, allure.Passed, byName["create"].Status)
+	require.Equal(t, allure.Skipped, byName["update"].Status)
+	require.Equal(t, allure.Skipped, byName["delete"].Status)
+	require.Equal(t,
+		fmt.Sprintf("Prerequisite %s has not passed (status: skipped)", byName["update"].FullName),
+		byName["delete"].StatusDetails.Message,
+	)
+	require.Equal(t, byName["update"].FullName+": update is not supported", byName["delete"].StatusDetails.Trace)
+	require.Len(t, byName["delete"].Links, 1)
+	require.Equal(t, "Prerequisite "+byName["update"].FullName, byName["delete"].Links[0].Name)
+	require.Equal(t, byName["update"].UUID.String()+"-result.json", byName["delete"].Links[0].URL)
+}
+
+func TestRunner_DependsOn_linkPattern(t *testing.T) {
+	t.Setenv("ALLURE_LINK_PREREQUISITE_PATTERN", "https://reports.example.com/{testCaseId}/{uuid}")
+	sink := allure.NewMemorySink()
+
+	r := NewRunner(t, "suiteTest")
+	r.SetSink(sink)
+	r.DependsOn("use", "prepare")
+	r.NewTest("prepare", func(t provider.T) {
+		t.Skip("not ready")
+	})
+	r.NewTest("use", func(t provider.T) {})
+	r.RunTests()
+
+	results, err := sink.Results()
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	byName := make(map[string]*allure.Result, len(results))
+	for _, result := range results {
+		byName[result.Name] = result
+	}
+
+	prepare := byName["prepare"]
+	require.Len(t, byName["use"].Links, 1)
+	require.Equal(t, "Prerequisite "+prepare.FullName, byName["use"].Links[0].Name)
+	require.Equal(t,
+		fmt.Sprintf("https://reports.example.com/%s/%s", prepare.TestCaseID, prepare.UUID),
+		byName["use"].Links[0].URL,
+	)
+}
+
+func TestRunner_DependsOn_parallel(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		started []string
+	)
+
+	record := func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		started = append(started, name)
+	}
+
+	r := NewRunner(t, "suiteTest")
+	r.SetSink(allure.NewMemorySink())
+	r.DependsOn("use", "prepare")
+	r.NewTest("prepare", func(t provider.T) {
+		t.Parallel()
+		time.Sleep(10 * time.Millisecond)
+		record("prepare")
+	})
+	r.NewTest("use", func(t provider.T) {
+		t.Parallel()
+		record("use")
+	})
+	r.NewTest("independent", func(t provider.T) {
+		record("independent")
+	})
+	r.RunTests()
+
+	// the prerequisite is run sequentially, the dependent test is still parallel
+	require.Equal(t, []string{"independent", "prepare", "use"}, started)
+}
+
+func TestCheckDependencies(t *testing.T) {
+	require.NoError(t, checkDependencies(map[string][]string{
+		"update": {"create"},
+		"delete": {"update", "create"},
+	}))
+
+	err := checkDependencies(map[string][]string{
+		"create": {"delete"},
+		"update": {"create"},
+		"delete": {"update"},
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "dependency cycle of the tests")
+
+	require.Error(t, checkDependencies(map[string][]string{"self": {"self"}}))
+}
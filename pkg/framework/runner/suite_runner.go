@@ -11,7 +11,9 @@ import (
 
 	"github.com/ozontech/allure-go/pkg/allure"
 	"github.com/ozontech/allure-go/pkg/framework/core/allure_manager/adapter"
+	"github.com/ozontech/allure-go/pkg/framework/core/allure_manager/labelfilter"
 	"github.com/ozontech/allure-go/pkg/framework/core/allure_manager/manager"
+	"github.com/ozontech/allure-go/pkg/framework/core/allure_manager/mutelist"
 	"github.com/ozontech/allure-go/pkg/framework/core/allure_manager/testplan"
 	"github.com/ozontech/allure-go/pkg/framework/core/common"
 	"github.com/ozontech/allure-go/pkg/framework/provider"
@@ -75,9 +77,11 @@ func newSuiteRunner(
 	}
 
 	testRunner := &runner{
-		internalT: newT,
-		testPlan:  testPlan,
-		tests:     make(map[string]Test),
+		internalT:   newT,
+		testPlan:    testPlan,
+		labelFilter: labelfilter.GetLabelFilter(),
+		muteList:    mutelist.GetMuteList(),
+		tests:       make(map[string]Test),
 	}
 
 	r := &suiteRunner{
@@ -91,10 +95,38 @@ func newSuiteRunner(
 		initializeParametrizedTests(r)
 	}
 
+	if sinkSuite, ok := suite.(AllureSinkSuite); ok {
+		r.SetSink(sinkSuite.GetSink())
+	}
+
+	if strategySuite, ok := suite.(AllureIDStrategySuite); ok {
+		r.SetIDStrategy(strategySuite.GetIDStrategy())
+	}
+
+	if retrySuite, ok := suite.(AllureRetrySuite); ok {
+		r.retryPolicy = retrySuite.RetryPolicy
+	}
+
+	if timeoutSuite, ok := suite.(AllureTimeoutSuite); ok {
+		r.timeoutPolicy = timeoutSuite.TimeoutPolicy
+	}
+
+	if labelsSuite, ok := suite.(AllureLabelsSuite); ok {
+		r.labels = labelsSuite.GetLabels
+	}
+
+	if prioritySuite, ok := suite.(AllurePrioritySuite); ok {
+		r.priority = prioritySuite.GetPriority
+	}
+
 	collectTests(r, suite)
 	collectParametrizedTests(r, suite)
 	collectHooks(r, suite)
 
+	if dependencySuite, ok := suite.(AllureDependencySuite); ok {
+		collectDependencies(r, dependencySuite)
+	}
+
 	return r
 }
 
@@ -217,6 +249,7 @@ func getParamTests(parentTest Test, params map[string]interface{}) map[string]Te
 			tags = append(tags, tag.GetValue())
 		}
 
+		paramName := strings.TrimPrefix(result.Name, tableTestPrefix)
 		res := make(map[string]Test, len(params))
 
 		for pName, param := range params {
@@ -230,6 +263,11 @@ func getParamTests(parentTest Test, params map[string]interface{}) map[string]Te
 				meta.GetResult().AddLabel(allure.IDAllureLabel(ptp.GetAllureID()))
 			}
 
+			// the hidden parameter separates the histories of the cases (see allure.ParametersIDStrategy),
+			// the report shows the case by its name
+			meta.GetResult().Parameters = append(meta.GetResult().Parameters, allure.NewParameter(paramName, param).Hidden())
+			meta.GetResult().UpdateIDs()
+
 			res[pName] = &testMethod{
 				testMeta: meta,
 				testBody: paramTest.GetRawBody(),
@@ -293,7 +331,7 @@ var matchMethod = flag.String("allure-go.m", "", "regular expression to select t
 var regFilter = newRegFilter()
 
 // Filtering method according to set regular expression
-// specified command-line argument -m
+// specified command-line argument -m, `$ALLURE_FILTER` or `filter` of the allure-go config file if it is empty
 func methodFilter(name string) (bool, error) {
 	// TODO: do we need regex here? Refactor later to use string prefix comparison
 
@@ -301,7 +339,12 @@ func methodFilter(name string) (bool, error) {
 		return false, nil
 	}
 
-	return regexp.MatchString(*matchMethod, name)
+	pattern := *matchMethod
+	if pattern == "" {
+		pattern = allure.GetConfig().Filter
+	}
+
+	return regexp.MatchString(pattern, name)
 }
 
 func newRegFilter() regexp.Regexp {
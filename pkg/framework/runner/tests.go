@@ -14,7 +14,8 @@ type suiteResult struct {
 	Container   *allure.Container `json:"container,omitempty"`
 	TestResults []TestResult      `json:"test_results,omitempty"`
 
-	mu sync.Mutex
+	errs []error
+	mu   sync.Mutex
 }
 
 // NewSuiteResult Returns new SuiteResult
@@ -65,6 +66,22 @@ func (sr *suiteResult) GetResultByUUID(uuid string) TestResult {
 	return nil
 }
 
+// AddError appends the error occurred while writing the suite results
+func (sr *suiteResult) AddError(err error) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	sr.errs = append(sr.errs, err)
+}
+
+// GetErrors returns all errors occurred while writing the suite results
+func (sr *suiteResult) GetErrors() []error {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	return sr.errs
+}
+
 // ToJSON marshall result to Json object
 //
 // Deprecated: use [json.Marshal] instead.
@@ -0,0 +1,413 @@
+package runner
+
+import (
+	"flag"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ozontech/allure-go/pkg/allure"
+	"github.com/ozontech/allure-go/pkg/framework/core/common"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+var timeoutFlag = flag.Duration("allure-go.timeout", 0, "timeout of each allure-go test, $ALLURE_TIMEOUT if not set")
+
+// SetTimeout sets the timeout of each test of the runner.
+// By default, it is taken from `-allure-go.timeout` flag or `$ALLURE_TIMEOUT`;
+// AllureTimeoutSuite and provider.T.WithTimeout override it for the test.
+func (r *runner) SetTimeout(timeout time.Duration) {
+	r.timeout = &timeout
+}
+
+// timeoutOf returns the timeout of the test, zero if the test has no timeout
+func (r *runner) timeoutOf(testName string) time.Duration {
+	if r.timeoutPolicy != nil {
+		if timeout := r.timeoutPolicy(testName); timeout > 0 {
+			return timeout
+		}
+	}
+
+	switch {
+	case r.timeout != nil:
+		return *r.timeout
+	case *timeoutFlag > 0:
+		return *timeoutFlag
+	default:
+		timeout, _ := time.ParseDuration(allure.GetConfig().Timeout)
+		return timeout
+	}
+}
+
+// deadline is the timeout of the running test counted from its start, the test can change it with provider.T.WithTimeout
+type deadline struct {
+	mu      sync.Mutex
+	start   time.Time
+	timeout time.Duration
+	timer   *time.Timer
+
+	// inline is true if the test has no timeout at its start, its body runs in the goroutine of the test (see run).
+	// The inline deadline doesn't expire: when it passes, the context of the test is cancelled by cancel
+	// and the goroutine dump is taken, the test is finalized as timed out when its body returns.
+	inline bool
+	cancel func()
+	dump   []byte
+
+	expired chan struct{}
+	once    sync.Once
+}
+
+func newDeadline(timeout time.Duration) *deadline {
+	d := &deadline{start: time.Now(), inline: timeout == 0, expired: make(chan struct{})}
+	d.set(timeout)
+
+	return d
+}
+
+// onOverdue sets the function called when the inline deadline passes
+func (d *deadline) onOverdue(cancel func()) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.cancel = cancel
+}
+
+// overdue returns the goroutine dump taken when the inline deadline has passed and true if it has passed
+func (d *deadline) overdue() ([]byte, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.dump, d.dump != nil
+}
+
+// set sets the timeout, zero timeout disables it. The deadline already passed expires at once.
+func (d *deadline) set(timeout time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+
+	d.timeout = timeout
+	if timeout > 0 {
+		d.timer = time.AfterFunc(time.Until(d.start.Add(timeout)), d.expire)
+	}
+}
+
+// get returns the timeout
+func (d *deadline) get() time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.timeout
+}
+
+// stop disables the timer of the deadline
+func (d *deadline) stop() {
+	d.set(0)
+}
+
+// pause stops the timer of the deadline keeping the timeout, the deadline is started again by restart
+func (d *deadline) pause() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+}
+
+// restart counts the timeout from now
+func (d *deadline) restart() {
+	d.mu.Lock()
+	d.start = time.Now()
+	d.mu.Unlock()
+
+	d.set(d.get())
+}
+
+func (d *deadline) expire() {
+	d.mu.Lock()
+	inline, cancel := d.inline, d.cancel
+	if inline && d.dump == nil {
+		d.dump = goroutineDump()
+	}
+	d.mu.Unlock()
+
+	if !inline {
+		d.once.Do(func() { close(d.expired) })
+		return
+	}
+
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// isExpired reports whether the deadline has passed
+func (d *deadline) isExpired() bool {
+	select {
+	case <-d.expired:
+		return true
+	default:
+		return false
+	}
+}
+
+// run runs the body in the new goroutine and waits until it finishes or the deadline passes.
+// Returns false if the deadline has passed, the body keeps running in the background then.
+// The inline deadline runs the body in the current goroutine, so runtime.Goexit, t.FailNow and the panics
+// of the test without the timeout behave as without the runner; it always returns true.
+func (d *deadline) run(body func()) bool {
+	if d.inline {
+		body()
+		return true
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		body()
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-d.expired:
+		return false
+	}
+}
+
+// timeoutTest finalizes the test exceeded its timeout: the context of the test is cancelled, the result is marked broken
+// with the goroutine dump attached and the after each hook is run. The test body can't be stopped, it keeps running
+// in the background and changing its result, so the test is finalized with the snapshot of the result (see allure.Result.Snapshot).
+// Returns the meta of the snapshot.
+func timeoutTest(t TestingT, parentProvider provider.Provider, testT *common.Common, timeout time.Duration, hooks testHooks) provider.TestMeta {
+	msg := fmt.Sprintf("Test timed out after %s", timeout)
+	testT.CancelContext()
+
+	meta := &snapshotMeta{TestMeta: testT.GetProvider().GetTestMeta(), result: testT.GetResult().Snapshot()}
+
+	hookT := newTestT(t, parentProvider)
+	hookT.SetTestMeta(meta)
+
+	hookT.GetProvider().StopResult(allure.Broken)
+	hookT.GetProvider().UpdateResultStatus(msg, msg)
+	meta.result.Attachments = append(meta.result.Attachments,
+		allure.NewAttachment("Goroutine dump", allure.Text, goroutineDump()))
+
+	t.Errorf("%s", msg)
+
+	_, _ = runHook(hookT, hooks.afterEach)
+
+	return meta
+}
+
+// overdueTest finalizes the inline test exceeded the timeout set by provider.T.WithTimeout: the test has already returned,
+// so it is marked broken with the goroutine dump taken at the moment of the timeout.
+func overdueTest(t TestingT, testT *common.Common, timeout time.Duration, dump []byte) {
+	msg := fmt.Sprintf("Test timed out after %s", timeout)
+
+	testT.GetProvider().StopResult(allure.Broken)
+	testT.GetProvider().UpdateResultStatus(msg, msg)
+	testT.GetResult().Attachments = append(testT.GetResult().Attachments, allure.NewAttachment("Goroutine dump", allure.Text, dump))
+
+	t.Errorf("%s", msg)
+}
+
+// snapshotMeta is the meta of the timed out test detached from its body: it has the snapshot of the result,
+// the hooks and the container are shared with the test
+type snapshotMeta struct {
+	provider.TestMeta
+
+	result *allure.Result
+}
+
+// GetResult returns the snapshot of the result
+func (m *snapshotMeta) GetResult() *allure.Result {
+	return m.result
+}
+
+// SetResult replaces the snapshot of the result
+func (m *snapshotMeta) SetResult(result *allure.Result) {
+	m.result = result
+}
+
+// goroutineDump returns the stack traces of all goroutines
+func goroutineDump() []byte {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// timeoutT is the TestingT of the test with a deadline. After the deadline has passed the calls of the test body
+// running in the background are ignored: the test is already finished.
+type timeoutT struct {
+	TestingT
+
+	deadline *deadline
+	parallel *sync.Once
+
+	mu           sync.Mutex
+	lateCleanups []func()
+}
+
+// Parallel signals that the test is to be run in parallel, it is ignored for the prerequisite of the other tests (see DependsOn).
+// The test is paused until its sequential siblings finish, the pause doesn't count toward the timeout.
+func (t *timeoutT) Parallel() {
+	if t.deadline.isExpired() {
+		runtime.Goexit()
+	}
+
+	t.parallel.Do(func() {
+		t.deadline.pause()
+		defer t.deadline.restart()
+
+		t.TestingT.Parallel()
+	})
+}
+
+// Fail marks the test as failed
+func (t *timeoutT) Fail() {
+	if !t.deadline.isExpired() {
+		t.TestingT.Fail()
+	}
+}
+
+// FailNow marks the test as failed and stops it
+func (t *timeoutT) FailNow() {
+	if t.deadline.isExpired() {
+		runtime.Goexit()
+	}
+
+	t.TestingT.FailNow()
+}
+
+// Error logs the message and marks the test as failed
+func (t *timeoutT) Error(args ...interface{}) {
+	if !t.deadline.isExpired() {
+		t.TestingT.Helper()
+		t.TestingT.Error(args...)
+	}
+}
+
+// Errorf logs the formatted message and marks the test as failed
+func (t *timeoutT) Errorf(format string, args ...interface{}) {
+	if !t.deadline.isExpired() {
+		t.TestingT.Helper()
+		t.TestingT.Errorf(format, args...)
+	}
+}
+
+// Fatal logs the message, marks the test as failed and stops it
+func (t *timeoutT) Fatal(args ...interface{}) {
+	if t.deadline.isExpired() {
+		runtime.Goexit()
+	}
+
+	t.TestingT.Helper()
+	t.TestingT.Fatal(args...)
+}
+
+// Fatalf logs the formatted message, marks the test as failed and stops it
+func (t *timeoutT) Fatalf(format string, args ...interface{}) {
+	if t.deadline.isExpired() {
+		runtime.Goexit()
+	}
+
+	t.TestingT.Helper()
+	t.TestingT.Fatalf(format, args...)
+}
+
+// Log logs the message
+func (t *timeoutT) Log(args ...interface{}) {
+	if !t.deadline.isExpired() {
+		t.TestingT.Helper()
+		t.TestingT.Log(args...)
+	}
+}
+
+// Logf logs the formatted message
+func (t *timeoutT) Logf(format string, args ...interface{}) {
+	if !t.deadline.isExpired() {
+		t.TestingT.Helper()
+		t.TestingT.Logf(format, args...)
+	}
+}
+
+// Skip logs the message, marks the test as skipped and stops it
+func (t *timeoutT) Skip(args ...interface{}) {
+	if t.deadline.isExpired() {
+		runtime.Goexit()
+	}
+
+	t.TestingT.Helper()
+	t.TestingT.Skip(args...)
+}
+
+// Skipf logs the formatted message, marks the test as skipped and stops it
+func (t *timeoutT) Skipf(format string, args ...interface{}) {
+	if t.deadline.isExpired() {
+		runtime.Goexit()
+	}
+
+	t.TestingT.Helper()
+	t.TestingT.Skipf(format, args...)
+}
+
+// SkipNow marks the test as skipped and stops it
+func (t *timeoutT) SkipNow() {
+	if t.deadline.isExpired() {
+		runtime.Goexit()
+	}
+
+	t.TestingT.SkipNow()
+}
+
+// Cleanup registers the function called when the test finishes. The function registered by the body running after
+// the deadline is called when the body returns: the test is already finished.
+func (t *timeoutT) Cleanup(f func()) {
+	if t.deadline.isExpired() {
+		t.mu.Lock()
+		t.lateCleanups = append(t.lateCleanups, f)
+		t.mu.Unlock()
+
+		return
+	}
+
+	t.TestingT.Cleanup(f)
+}
+
+// runLateCleanups calls the cleanups registered after the deadline in the reverse order
+func (t *timeoutT) runLateCleanups() {
+	t.mu.Lock()
+	cleanups := t.lateCleanups
+	t.lateCleanups = nil
+	t.mu.Unlock()
+
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		cleanups[i]()
+	}
+}
+
+// Run runs the subtest, it is not started after the deadline
+func (t *timeoutT) Run(testName string, testBody func(t *testing.T)) bool {
+	if t.deadline.isExpired() {
+		return false
+	}
+
+	return t.TestingT.Run(testName, testBody)
+}
@@ -1,11 +1,14 @@
 package suite
 
 import (
+	"fmt"
 	"os"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/ozontech/allure-go/pkg/allure"
 	"github.com/ozontech/allure-go/pkg/framework/provider"
 	"github.com/ozontech/allure-go/pkg/framework/runner"
 	"github.com/stretchr/testify/require"
@@ -17,6 +20,7 @@ type suiteRunnerTMock struct {
 	t        *testing.T
 	failNow  bool
 	parallel bool
+	fatal    string
 }
 
 func (m *suiteRunnerTMock) Name() string {
@@ -31,6 +35,11 @@ func (m *suiteRunnerTMock) FailNow() {
 	m.failNow = true
 }
 
+func (m *suiteRunnerTMock) Fatalf(format string, args ...interface{}) {
+	m.fatal = fmt.Sprintf(format, args...)
+	m.failNow = true
+}
+
 func (m *suiteRunnerTMock) Run(testName string, testBody func(t *testing.T)) bool {
 	testBody(m.t)
 	return true
@@ -168,3 +177,296 @@ func TestSuiteRunner_StartTimeAndDurationOfConsistentTests(t *testing.T) {
 	require.Equal(t, time.UnixMilli(results[0].GetResult().Stop-results[0].GetResult().Start).Second(), 1)
 	require.Equal(t, time.UnixMilli(results[1].GetResult().Stop-results[1].GetResult().Start).Second(), 1)
 }
+
+type TestSuiteSink struct {
+	Suite
+	sink *allure.MemorySink
+}
+
+func (s *TestSuiteSink) GetSink() allure.Sink {
+	return s.sink
+}
+
+func (s *TestSuiteSink) TestSome(t provider.T) {
+	t.Title("sink test")
+}
+
+func TestSuiteRunner_Sink(t *testing.T) {
+	suite := &TestSuiteSink{sink: allure.NewMemorySink()}
+	runner.NewSuiteRunner(t, "packageName", "suiteName", suite).RunTests()
+
+	results, err := suite.sink.Results()
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "sink test", results[0].Name)
+}
+
+type TestSuiteIDStrategy struct {
+	Suite
+	sink       *allure.MemorySink
+	ParamCases []string
+}
+
+func (s *TestSuiteIDStrategy) GetSink() allure.Sink {
+	return s.sink
+}
+
+func (s *TestSuiteIDStrategy) GetIDStrategy() allure.IDStrategy {
+	return allure.FullNameIDStrategy{}
+}
+
+func (s *TestSuiteIDStrategy) BeforeAll(t provider.T) {
+	s.ParamCases = []string{"first", "second"}
+}
+
+func (s *TestSuiteIDStrategy) TableTestCases(t provider.T, name string) {
+	t.Require().NotEmpty(name)
+}
+
+func TestSuiteRunner_IDStrategy(t *testing.T) {
+	suite := &TestSuiteIDStrategy{sink: allure.NewMemorySink()}
+	runner.NewSuiteRunner(t, "packageName", "suiteName", suite).RunTests()
+
+	results, err := suite.sink.Results()
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	for _, result := range results {
+		require.Len(t, result.Parameters, 1)
+		require.Equal(t, "Cases", result.Parameters[0].Name)
+		require.Equal(t, allure.ParameterModeHidden, result.Parameters[0].GetMode())
+		require.Equal(t, allure.FullNameIDStrategy{}.TestCaseID(result), result.TestCaseID)
+		require.Equal(t, allure.FullNameIDStrategy{}.HistoryID(result), result.HistoryID)
+	}
+
+	require.NotEqual(t, results[0].HistoryID, results[1].HistoryID)
+}
+
+type TestSuiteRetries struct {
+	Suite
+	sink     *allure.MemorySink
+	attempts map[string]int
+}
+
+func (s *TestSuiteRetries) GetSink() allure.Sink {
+	return s.sink
+}
+
+func (s *TestSuiteRetries) RetryPolicy(testName string) int {
+	if testName == "TestRetried" {
+		return 1
+	}
+
+	return 0
+}
+
+func (s *TestSuiteRetries) TestRetried(t provider.T) {
+	s.attempts[t.Name()]++
+	t.Require().Greater(s.attempts[t.Name()], 1)
+}
+
+func (s *TestSuiteRetries) TestNotRetried(t provider.T) {
+	s.attempts[t.Name()]++
+}
+
+func TestSuiteRunner_Retries(t *testing.T) {
+	suite := &TestSuiteRetries{sink: allure.NewMemorySink(), attempts: make(map[string]int)}
+	runner.NewSuiteRunner(t, "packageName", "suiteName", suite).RunTests()
+
+	require.Equal(t, map[string]int{"TestRetried": 2, "TestNotRetried": 1}, suite.attempts)
+
+	results, err := suite.sink.Results()
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+}
+
+type TestSuiteTimeouts struct {
+	Suite
+	sink     *allure.MemorySink
+	release  chan struct{}
+	attempts int32
+}
+
+func (s *TestSuiteTimeouts) GetSink() allure.Sink {
+	return s.sink
+}
+
+func (s *TestSuiteTimeouts) RetryPolicy(testName string) int {
+	return 1
+}
+
+func (s *TestSuiteTimeouts) TimeoutPolicy(testName string) time.Duration {
+	if testName == "TestHung" {
+		return 20 * time.Millisecond
+	}
+
+	return 0
+}
+
+func (s *TestSuiteTimeouts) TestHung(t provider.T) {
+	if atomic.AddInt32(&s.attempts, 1) == 1 {
+		<-s.release
+	}
+}
+
+func TestSuiteRunner_Timeouts(t *testing.T) {
+	suite := &TestSuiteTimeouts{sink: allure.NewMemorySink(), release: make(chan struct{})}
+	defer close(suite.release)
+
+	runner.NewSuiteRunner(t, "packageName", "suiteName", suite).RunTests()
+
+	results, err := suite.sink.Results()
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	statuses := map[allure.Status]int{}
+	for _, result := range results {
+		statuses[result.Status]++
+	}
+	require.Equal(t, map[allure.Status]int{allure.Broken: 1, allure.Passed: 1}, statuses)
+}
+
+type TestSuitePriority struct {
+	Suite
+	started []string
+}
+
+func (s *TestSuitePriority) GetPriority(testName string) int {
+	if testName == "TestLast" {
+		return -1
+	}
+
+	if testName == "TestSmoke" {
+		return 10
+	}
+
+	return 0
+}
+
+func (s *TestSuitePriority) TestLast(t provider.T) {
+	s.started = append(s.started, t.Name())
+}
+
+func (s *TestSuitePriority) TestA(t provider.T) {
+	s.started = append(s.started, t.Name())
+}
+
+func (s *TestSuitePriority) TestSmoke(t provider.T) {
+	s.started = append(s.started, t.Name())
+}
+
+func (s *TestSuitePriority) TestB(t provider.T) {
+	s.started = append(s.started, t.Name())
+}
+
+func TestSuiteRunner_Priority(t *testing.T) {
+	suite := &TestSuitePriority{}
+	runner.NewSuiteRunner(t, "packageName", "suiteName", suite).RunTests()
+
+	require.Equal(t, []string{"TestSmoke", "TestA", "TestB", "TestLast"}, suite.started)
+}
+
+type TestSuiteDependencies struct {
+	Suite
+	sink    *allure.MemorySink
+	started []string
+}
+
+func (s *TestSuiteDependencies) GetSink() allure.Sink {
+	return s.sink
+}
+
+func (s *TestSuiteDependencies) DependsOn(testName string) []string {
+	switch testName {
+	case "TestUpdate":
+		return []string{"TestCreate"}
+	case "TestDelete":
+		return []string{"TestUpdate"}
+	}
+
+	return nil
+}
+
+func (s *TestSuiteDependencies) TestDelete(t provider.T) {
+	s.started = append(s.started, t.Name())
+}
+
+func (s *TestSuiteDependencies) TestUpdate(t provider.T) {
+	s.started = append(s.started, t.Name())
+}
+
+func (s *TestSuiteDependencies) TestCreate(t provider.T) {
+	s.started = append(s.started, t.Name())
+}
+
+func TestSuiteRunner_Dependencies(t *testing.T) {
+	suite := &TestSuiteDependencies{sink: allure.NewMemorySink()}
+	runner.NewSuiteRunner(t, "packageName", "suiteName", suite).RunTests()
+
+	require.Equal(t, []string{"TestCreate", "TestUpdate", "TestDelete"}, suite.started)
+}
+
+type TestSuiteDependencyCycle struct {
+	Suite
+}
+
+func (s *TestSuiteDependencyCycle) DependsOn(testName string) []string {
+	if testName == "TestFirst" {
+		return []string{"TestSecond"}
+	}
+
+	return []string{"TestFirst"}
+}
+
+func (s *TestSuiteDependencyCycle) TestFirst(t provider.T) {}
+
+func (s *TestSuiteDependencyCycle) TestSecond(t provider.T) {}
+
+func TestSuiteRunner_DependencyCycle(t *testing.T) {
+	mockT := &suiteRunnerTMock{t: t}
+	runner.NewSuiteRunner(mockT, "packageName", "suiteName", new(TestSuiteDependencyCycle))
+
+	require.True(t, mockT.failNow)
+	require.Contains(t, mockT.fatal, "dependency cycle of the tests")
+}
+
+type TestSuiteLabels struct {
+	Suite
+	sink *allure.MemorySink
+}
+
+func (s *TestSuiteLabels) GetSink() allure.Sink {
+	return s.sink
+}
+
+func (s *TestSuiteLabels) GetLabels(testName string) []*allure.Label {
+	if testName == "TestSmoke" {
+		return []*allure.Label{allure.TagLabel("smoke"), allure.SeverityLabel(allure.CRITICAL)}
+	}
+
+	return nil
+}
+
+func (s *TestSuiteLabels) TestSmoke(t provider.T) {}
+
+func (s *TestSuiteLabels) TestOther(t provider.T) {}
+
+func TestSuiteRunner_Labels(t *testing.T) {
+	suite := &TestSuiteLabels{sink: allure.NewMemorySink()}
+	runner.NewSuiteRunner(t, "packageName", "suiteName", suite).RunTests()
+
+	results, err := suite.sink.Results()
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	for _, result := range results {
+		tags := result.GetLabels(allure.Tag)
+		if result.Name == "TestSmoke" {
+			require.Len(t, tags, 1)
+			require.Equal(t, "smoke", tags[0].GetValue())
+			continue
+		}
+
+		require.Empty(t, tags)
+	}
+}